@@ -0,0 +1,259 @@
+package mtransform
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// defaultEpsilon is the tolerance used by methods without a Tol variant.
+const defaultEpsilon = 1e-9
+
+var ErrNoFixedPoint = errors.New("mtransform: transform has no unique fixed point")
+
+// Determinant returns the determinant of the upper-left 2x2 linear block.
+func (t *Transform) Determinant() float64 {
+	return t[0][0]*t[1][1] - t[0][1]*t[1][0]
+}
+
+func (t *Transform) PreservesOrientation() bool {
+	return t.Determinant() > 0
+}
+
+func (t *Transform) FlipsOrientation() bool {
+	return t.Determinant() < 0
+}
+
+func (t *Transform) AreaScale() float64 {
+	return math.Abs(t.Determinant())
+}
+
+// SignedAreaScale is the factor applied to signed areas: AreaScale, negated
+// when the transform reverses winding. It is the same value as Determinant.
+func (t *Transform) SignedAreaScale() float64 {
+	return t.Determinant()
+}
+
+// AverageLengthScale returns the geometric mean of the singular values of the
+// linear block, which equals the square root of the area scale.
+func (t *Transform) AverageLengthScale() float64 {
+	return math.Sqrt(t.AreaScale())
+}
+
+// SingularValues returns the largest and smallest singular values of the
+// linear block, using the closed form 2x2 decomposition.
+func (t *Transform) SingularValues() (float64, float64) {
+	e := (t[0][0] + t[1][1]) / 2
+	f := (t[0][0] - t[1][1]) / 2
+	g := (t[1][0] + t[0][1]) / 2
+	h := (t[1][0] - t[0][1]) / 2
+	q := math.Hypot(e, h)
+	r := math.Hypot(f, g)
+	return q + r, math.Abs(q - r)
+}
+
+// MaxStretch is the largest factor by which any direction is lengthened, the
+// larger singular value.
+func (t *Transform) MaxStretch() float64 {
+	max, _ := t.SingularValues()
+	return max
+}
+
+// MinStretch is the smallest factor by which any direction is lengthened, the
+// smaller singular value.
+func (t *Transform) MinStretch() float64 {
+	_, min := t.SingularValues()
+	return min
+}
+
+// PolarDecompose splits the linear block into rotation·stretch, where stretch
+// is symmetric positive semi-definite. When the transform flips orientation
+// the rotation part carries the reflection. Translation is dropped from both.
+func (t *Transform) PolarDecompose() (rotation *Transform, stretch *Transform) {
+	sign := 1.0
+	if t.Determinant() < 0 {
+		sign = -1
+	}
+	a := t[0][0] + sign*t[1][1]
+	b := t[0][1] - sign*t[1][0]
+	c := t[1][0] - sign*t[0][1]
+	d := t[1][1] + sign*t[0][0]
+	n := math.Hypot(a, c)
+	rotation = NewTransform()
+	if n != 0 {
+		rotation[0][0], rotation[0][1] = a/n, b/n
+		rotation[1][0], rotation[1][1] = c/n, d/n
+	}
+	stretch = NewTransform()
+	stretch[0][0] = rotation[0][0]*t[0][0] + rotation[1][0]*t[1][0]
+	stretch[0][1] = rotation[0][0]*t[0][1] + rotation[1][0]*t[1][1]
+	stretch[1][0] = rotation[0][1]*t[0][0] + rotation[1][1]*t[1][0]
+	stretch[1][1] = rotation[0][1]*t[0][1] + rotation[1][1]*t[1][1]
+	return rotation, stretch
+}
+
+// Orthonormalize replaces the linear block with the nearest orthogonal matrix,
+// taken from the polar decomposition, keeping the translation. A reflection
+// present in the transform is kept.
+func (t *Transform) Orthonormalize() {
+	rotation, _ := t.PolarDecompose()
+	t[0][0], t[0][1] = rotation[0][0], rotation[0][1]
+	t[1][0], t[1][1] = rotation[1][0], rotation[1][1]
+}
+
+// NearestRigid returns the proper rotation closest to the linear block, in the
+// Frobenius sense, with t's translation. Scale and shear are dropped and,
+// unlike Orthonormalize, so is any reflection: the result always preserves
+// orientation. Without a reflection the rotation is the polar one.
+func (t *Transform) NearestRigid() *Transform {
+	angle := math.Atan2(t[1][0]-t[0][1], t[0][0]+t[1][1])
+	r := NewTransform()
+	r.Translate(t[0][2], t[1][2])
+	r.RotateOrigin(angle)
+	return r
+}
+
+func (t *Transform) IsFinite() bool {
+	return t.Validate() == nil
+}
+
+// Validate returns an error naming the first element that is NaN or infinite.
+func (t *Transform) Validate() error {
+	for i := range t {
+		for j, v := range t[i] {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return fmt.Errorf("mtransform: element [%d][%d] is %v", i, j, v)
+			}
+		}
+	}
+	return nil
+}
+
+// Degeneracy reports whether t has a non-finite element or is not
+// IsInvertible, with the reason: "contains NaN", "contains Inf", "zero x-scale"
+// or "zero y-scale" when the mapped x or y axis has vanished, or "collinear
+// axes" when both survive but are parallel. The reason is empty otherwise.
+func (t *Transform) Degeneracy() (bool, string) {
+	for i := range t {
+		for _, v := range t[i] {
+			if math.IsNaN(v) {
+				return true, "contains NaN"
+			}
+		}
+	}
+	if !t.IsFinite() {
+		return true, "contains Inf"
+	}
+	if t.IsInvertible() {
+		return false, ""
+	}
+	col0 := t[0][0]*t[0][0] + t[1][0]*t[1][0]
+	col1 := t[0][1]*t[0][1] + t[1][1]*t[1][1]
+	switch n := col0 + col1; {
+	case col0 <= 1e-12*n:
+		return true, "zero x-scale"
+	case col1 <= 1e-12*n:
+		return true, "zero y-scale"
+	}
+	return true, "collinear axes"
+}
+
+// GetRotation returns the angle of the mapped x axis in radians, in (-π, π].
+func (t *Transform) GetRotation() float64 {
+	return math.Atan2(t[1][0], t[0][0])
+}
+
+// GetRotationUnwrapped returns the rotation angle, shifted by a multiple of 2π
+// to lie as close as possible to previous, so an animated angle does not jump
+// when it crosses ±π.
+func (t *Transform) GetRotationUnwrapped(previous float64) float64 {
+	a := t.GetRotation()
+	return a + 2*math.Pi*math.Round((previous-a)/(2*math.Pi))
+}
+
+func (t *Transform) GetRotationDeg() float64 {
+	return degrees(t.GetRotation())
+}
+
+// FrobeniusNorm returns the square root of the sum of the squared elements.
+func (t *Transform) FrobeniusNorm() float64 {
+	var sum float64
+	for i := range t {
+		for _, v := range t[i] {
+			sum += v * v
+		}
+	}
+	return math.Sqrt(sum)
+}
+
+// Distance returns the Frobenius norm of the element-wise difference.
+func (t *Transform) Distance(other *Transform) float64 {
+	var d Transform
+	for i := range t {
+		for j := range t[i] {
+			d[i][j] = t[i][j] - other[i][j]
+		}
+	}
+	return d.FrobeniusNorm()
+}
+
+// Trace returns the sum of the diagonal of the linear block.
+func (t *Transform) Trace() float64 {
+	return t[0][0] + t[1][1]
+}
+
+// Eigenvalues returns the roots of the characteristic polynomial of the linear
+// block. Rotational components give a complex-conjugate pair.
+func (t *Transform) Eigenvalues() (complex128, complex128) {
+	m := complex(t.Trace()/2, 0)
+	r := cmplx.Sqrt(m*m - complex(t.Determinant(), 0))
+	return m + r, m - r
+}
+
+// FixedPoint returns the point that the transform maps onto itself, solving
+// (M - I)p = -translation. Pure translations, and any transform whose linear
+// block has an eigenvalue of 1, have no unique fixed point. As in IsInvertible
+// the test is relative, |det(M-I)| > 1e-12·|M-I|², so a rotation by a tiny
+// angle still has its pivot, and it does not depend on the units of the
+// translation. M-I at the level of rounding noise in M, such as after a
+// rotate/unrotate round trip, counts as zero.
+func (t *Transform) FixedPoint() (Point, error) {
+	a, b := t[0][0]-1, t[0][1]
+	c, d := t[1][0], t[1][1]-1
+	det := a*d - b*c
+	n := a*a + b*b + c*c + d*d
+	m := t[0][0]*t[0][0] + t[0][1]*t[0][1] + t[1][0]*t[1][0] + t[1][1]*t[1][1]
+	if !(math.Abs(det) > 1e-12*n) || n <= 1e-28*m {
+		return Point{}, ErrNoFixedPoint
+	}
+	return Point{
+		X: (-t[0][2]*d + b*t[1][2]) / det,
+		Y: (-a*t[1][2] + c*t[0][2]) / det,
+	}, nil
+}
+
+func (t *Transform) IsIdentity() bool {
+	id := Identity()
+	return t.Equals(&id)
+}
+
+// IsIdentityTol reports whether every element is within epsilon of the
+// identity, which is what survives a rotate/unrotate round trip.
+func (t *Transform) IsIdentityTol(epsilon float64) bool {
+	id := Identity()
+	return t.IsNearlyEqual(&id, epsilon)
+}
+
+// IsOrthogonal reports whether the linear block is a rotation or reflection,
+// within defaultEpsilon.
+func (t *Transform) IsOrthogonal() bool {
+	return t.IsOrthogonalTol(defaultEpsilon)
+}
+
+func (t *Transform) IsOrthogonalTol(epsilon float64) bool {
+	col0 := t[0][0]*t[0][0] + t[1][0]*t[1][0]
+	col1 := t[0][1]*t[0][1] + t[1][1]*t[1][1]
+	dot := t[0][0]*t[0][1] + t[1][0]*t[1][1]
+	return math.Abs(col0-1) <= epsilon && math.Abs(col1-1) <= epsilon && math.Abs(dot) <= epsilon
+}
@@ -0,0 +1,321 @@
+package mtransform
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+const tolerance = 1e-9
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+func TestOrientation(t *testing.T) {
+	r := NewTransform()
+	r.ReflectX()
+	if !r.FlipsOrientation() || r.PreservesOrientation() {
+		t.Errorf("ReflectX: expected flipped orientation, determinant %v", r.Determinant())
+	}
+	o := NewTransform()
+	o.RotateOrigin(math.Pi / 3)
+	if !o.PreservesOrientation() || o.FlipsOrientation() {
+		t.Errorf("RotateOrigin: expected preserved orientation, determinant %v", o.Determinant())
+	}
+}
+
+func TestAreaScale(t *testing.T) {
+	s := NewTransform()
+	s.Scale(2, 3)
+	if got := s.AreaScale(); !near(got, 6) {
+		t.Errorf("AreaScale of Scale(2,3): got %v, want 6", got)
+	}
+	if got := s.AverageLengthScale(); !near(got, math.Sqrt(6)) {
+		t.Errorf("AverageLengthScale of Scale(2,3): got %v, want %v", got, math.Sqrt(6))
+	}
+	r := NewTransform()
+	r.RotateOrigin(0.7)
+	if got := r.AreaScale(); !near(got, 1) {
+		t.Errorf("AreaScale of rotation: got %v, want 1", got)
+	}
+	if got := s.SignedAreaScale(); !near(got, 6) {
+		t.Errorf("SignedAreaScale of Scale(2,3): got %v, want 6", got)
+	}
+	r.ReflectX()
+	if got := r.SignedAreaScale(); !near(got, -1) {
+		t.Errorf("SignedAreaScale after ReflectX: got %v, want -1", got)
+	}
+}
+
+func TestSingularValues(t *testing.T) {
+	s := NewTransform()
+	s.Scale(4, 1)
+	max, min := s.SingularValues()
+	if !near(max, 4) || !near(min, 1) {
+		t.Errorf("SingularValues of Scale(4,1): got %v, %v, want 4, 1", max, min)
+	}
+	s.RotateOrigin(1.1)
+	s.Translate(5, -2)
+	max, min = s.SingularValues()
+	if !near(max, 4) || !near(min, 1) {
+		t.Errorf("SingularValues of rotated Scale(4,1): got %v, %v, want 4, 1", max, min)
+	}
+	k := NewTransform()
+	k.SkewX(math.Pi / 4)
+	max, min = k.SingularValues()
+	if !near(max*min, 1) || !near(max*max+min*min, 3) {
+		t.Errorf("SingularValues of SkewX(45°): got %v, %v", max, min)
+	}
+}
+
+func TestStretch(t *testing.T) {
+	s := NewTransform()
+	s.Scale(3, 1)
+	if got := s.MaxStretch(); !near(got, 3) {
+		t.Errorf("MaxStretch of Scale(3,1): got %v, want 3", got)
+	}
+	if got := s.MinStretch(); !near(got, 1) {
+		t.Errorf("MinStretch of Scale(3,1): got %v, want 1", got)
+	}
+	r := NewTRS(4, 5, 2.2, 1, 1)
+	if max, min := r.MaxStretch(), r.MinStretch(); !near(max, 1) || !near(min, 1) {
+		t.Errorf("MaxStretch, MinStretch of rotation: got %v, %v, want 1, 1", max, min)
+	}
+}
+
+func TestPolarDecompose(t *testing.T) {
+	r := NewTransform()
+	r.RotateOrigin(0.4)
+	rot, stretch := r.PolarDecompose()
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			if !near(stretch[i][j], Identity()[i][j]) || !near(rot[i][j], r[i][j]) {
+				t.Fatalf("PolarDecompose of rotation: got %v, %v", rot, stretch)
+			}
+		}
+	}
+
+	for _, m := range []Transform{
+		{{2, 0.5, 3}, {-0.3, 1.5, 4}, {0, 0, 1}},
+		{{1, 2, 0}, {0.5, -1, 0}, {0, 0, 1}},
+	} {
+		rot, stretch = m.PolarDecompose()
+		if !near(stretch[0][1], stretch[1][0]) {
+			t.Errorf("PolarDecompose of %v: stretch %v is not symmetric", m, stretch)
+		}
+		got := MultiplyTransforms(*rot, *stretch)
+		for i := 0; i < 2; i++ {
+			for j := 0; j < 2; j++ {
+				if !near(got[i][j], m[i][j]) {
+					t.Errorf("PolarDecompose of %v: rotation·stretch = %v", m, got)
+				}
+			}
+		}
+	}
+}
+
+func TestOrthonormalize(t *testing.T) {
+	want := NewTransform()
+	want.Translate(3, -4)
+	want.RotateOrigin(0.9)
+	got := *want
+	got.SkewX(1e-4)
+	got.Orthonormalize()
+	col0 := got[0][0]*got[0][0] + got[1][0]*got[1][0]
+	col1 := got[0][1]*got[0][1] + got[1][1]*got[1][1]
+	dot := got[0][0]*got[0][1] + got[1][0]*got[1][1]
+	if !near(col0, 1) || !near(col1, 1) || !near(dot, 0) {
+		t.Errorf("Orthonormalize: %v is not orthogonal", got)
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if math.Abs(got[i][j]-want[i][j]) > 1e-4 {
+				t.Errorf("Orthonormalize: got %v, want close to %v", got, *want)
+			}
+		}
+	}
+}
+
+func TestNearestRigid(t *testing.T) {
+	tr := NewTRS(3, -4, 0.9, 2.5, 2.5)
+	got := tr.NearestRigid()
+	if want := NewTRS(3, -4, 0.9, 1, 1); !nearTransform(got, want) {
+		t.Errorf("NearestRigid of scaled rotation: got %v, want %v", *got, *want)
+	}
+	if max, min := got.SingularValues(); !near(max, 1) || !near(min, 1) {
+		t.Errorf("NearestRigid: scale %v, %v, want 1, 1", max, min)
+	}
+
+	sheared := NewTRS(1, 2, -0.4, 3, 0.5)
+	sheared.SkewX(0.7)
+	rot, _ := sheared.PolarDecompose()
+	got = sheared.NearestRigid()
+	rot.SetTranslation(1, 2)
+	if !nearTransform(got, rot) {
+		t.Errorf("NearestRigid: got %v, want polar rotation %v", *got, *rot)
+	}
+
+	reflected := NewTRS(0, 0, 0.3, 2, -2)
+	if got := reflected.NearestRigid(); !got.PreservesOrientation() || !got.IsOrthogonal() {
+		t.Errorf("NearestRigid of reflection: got %v, want a proper rotation", *got)
+	}
+}
+
+func TestDegeneracy(t *testing.T) {
+	cases := []struct {
+		name   string
+		t      Transform
+		reason string
+	}{
+		{"rotation", *NewTRS(1, 2, 0.5, 2, 3), ""},
+		{"tiny but regular", *NewTRS(0, 0, 0.5, 1e-8, 1e-8), ""},
+		{"NaN", Transform{{1, 0, math.NaN()}, {0, 1, 0}, {0, 0, 1}}, "contains NaN"},
+		{"Inf", Transform{{1, 0, 0}, {0, math.Inf(-1), 0}, {0, 0, 1}}, "contains Inf"},
+		{"zero x", *NewTRS(1, 2, 0.5, 0, 3), "zero x-scale"},
+		{"zero y", *NewTRS(1, 2, 0.5, 3, 0), "zero y-scale"},
+		{"all zero", Transform{{0, 0, 4}, {0, 0, 5}, {0, 0, 1}}, "zero x-scale"},
+		{"collinear", Transform{{1, 2, 0}, {2, 4, 0}, {0, 0, 1}}, "collinear axes"},
+	}
+	for _, c := range cases {
+		degenerate, reason := c.t.Degeneracy()
+		if degenerate != (c.reason != "") || reason != c.reason {
+			t.Errorf("Degeneracy(%s): got %v, %q, want %q", c.name, degenerate, reason, c.reason)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	good := NewTransform()
+	good.Scale(2, 3)
+	if !good.IsFinite() || good.Validate() != nil {
+		t.Errorf("Validate: %v reported as non-finite", good)
+	}
+	bad := NewTransform()
+	bad.Scale(0, 1)
+	bad.Scale(1/bad[0][0], 1)
+	if bad.IsFinite() {
+		t.Errorf("IsFinite: %v reported as finite", bad)
+	}
+	err := bad.Validate()
+	if err == nil || err.Error() != "mtransform: element [0][0] is NaN" {
+		t.Errorf("Validate: got %v, want element [0][0] NaN error", err)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	if got := NewTransform().FrobeniusNorm(); !near(got, math.Sqrt(3)) {
+		t.Errorf("FrobeniusNorm of identity: got %v, want √3", got)
+	}
+	a := NewTRS(1, 2, 0.3, 4, 5)
+	if got := a.Distance(a.Clone()); got != 0 {
+		t.Errorf("Distance to itself: got %v, want 0", got)
+	}
+	prev := 0.0
+	for _, dx := range []float64{0.1, 1, 10} {
+		b := a.Clone()
+		b.Translate(dx, 0)
+		d := a.Distance(b)
+		if d <= prev {
+			t.Errorf("Distance: %v for offset %v is not larger than %v", d, dx, prev)
+		}
+		prev = d
+	}
+}
+
+func TestEigenvalues(t *testing.T) {
+	r := NewTransform()
+	r.RotateOrigin(0.6)
+	if got := r.Trace(); !near(got, 2*math.Cos(0.6)) {
+		t.Errorf("Trace of rotation: got %v, want %v", got, 2*math.Cos(0.6))
+	}
+	l1, l2 := r.Eigenvalues()
+	if l1 != cmplx.Conj(l2) || !near(cmplx.Abs(l1), 1) || !near(math.Abs(cmplx.Phase(l1)), 0.6) {
+		t.Errorf("Eigenvalues of rotation: got %v, %v", l1, l2)
+	}
+	s := NewTRS(5, 5, 0, 3, -2)
+	if l1, l2 := s.Eigenvalues(); l1 != 3 || l2 != -2 {
+		t.Errorf("Eigenvalues of Scale(3,-2): got %v, %v, want 3, -2", l1, l2)
+	}
+}
+
+func TestFixedPoint(t *testing.T) {
+	r := NewTransform()
+	r.RotatePoint(1.1, 3, 4)
+	p, err := r.FixedPoint()
+	if err != nil || !near(p.X, 3) || !near(p.Y, 4) {
+		t.Errorf("FixedPoint of rotation about (3,4): got %v, %v", p, err)
+	}
+	s := NewTRS(6, -2, 0.4, 2, 2)
+	p, err = s.FixedPoint()
+	if q := s.ApplyToPoint(p); err != nil || !near(q.X, p.X) || !near(q.Y, p.Y) {
+		t.Errorf("FixedPoint: %v maps to %v, %v", p, q, err)
+	}
+	if _, err := NewTRS(1, 2, 0, 1, 1).FixedPoint(); err != ErrNoFixedPoint {
+		t.Errorf("FixedPoint of translation: got %v, want %v", err, ErrNoFixedPoint)
+	}
+	for _, angle := range []float64{1e-6, 1e-9} {
+		tiny := NewTransform()
+		tiny.RotatePoint(angle, 300, -40)
+		if p, err := tiny.FixedPoint(); err != nil || math.Abs(p.X-300) > 1e-3 || math.Abs(p.Y+40) > 1e-3 {
+			t.Errorf("FixedPoint of %v rad rotation about (300,-40): got %v, %v", angle, p, err)
+		}
+	}
+	// A translation whose linear block picked up one ulp of rounding noise.
+	noise := Transform{{1 + 0x1p-52, -1e-17, 5}, {1e-17, 1 - 0x1p-53, 5}, {0, 0, 1}}
+	if _, err := noise.FixedPoint(); err != ErrNoFixedPoint {
+		t.Errorf("FixedPoint of noisy translation %v: got %v, want %v", noise, err, ErrNoFixedPoint)
+	}
+}
+
+func TestGetRotationUnwrapped(t *testing.T) {
+	prev := math.Pi - 0.01
+	r := NewTransform()
+	r.RotateOrigin(math.Pi + 0.02)
+	if got := r.GetRotation(); got > 0 {
+		t.Fatalf("GetRotation: got %v, expected a wrapped negative angle", got)
+	}
+	if got := r.GetRotationUnwrapped(prev); !near(got, math.Pi+0.02) {
+		t.Errorf("GetRotationUnwrapped: got %v, want %v", got, math.Pi+0.02)
+	}
+	if got := r.GetRotationUnwrapped(5 * math.Pi); !near(got, 5*math.Pi+0.02) {
+		t.Errorf("GetRotationUnwrapped after several turns: got %v, want %v", got, 5*math.Pi+0.02)
+	}
+	r = NewTransform()
+	r.RotateOrigin(-0.5)
+	if got := r.GetRotationUnwrapped(0); !near(got, -0.5) {
+		t.Errorf("GetRotationUnwrapped near 0: got %v, want -0.5", got)
+	}
+}
+
+func TestIsIdentityTol(t *testing.T) {
+	r := NewTransform()
+	for i := 0; i < 7; i++ {
+		r.RotateOrigin(0.3)
+	}
+	for i := 0; i < 7; i++ {
+		r.RotateOrigin(-0.3)
+	}
+	if r.IsIdentity() {
+		t.Errorf("IsIdentity: expected rounding error after a rotate/unrotate round trip")
+	}
+	if !r.IsIdentityTol(1e-9) {
+		t.Errorf("IsIdentityTol: got false for %v", *r)
+	}
+	if !NewTransform().IsIdentity() || NewTRS(1e-6, 0, 0, 1, 1).IsIdentityTol(1e-9) {
+		t.Errorf("IsIdentity: wrong result for exact cases")
+	}
+
+	if !r.IsOrthogonal() || !r.IsOrthogonalTol(1e-12) {
+		t.Errorf("IsOrthogonal: got false for %v", *r)
+	}
+	s := NewTRS(0, 0, 0.3, 1+1e-6, 1)
+	if s.IsOrthogonal() || !s.IsOrthogonalTol(1e-5) {
+		t.Errorf("IsOrthogonalTol: wrong result for slightly scaled rotation %v", *s)
+	}
+	f := NewTransform()
+	f.ReflectX()
+	if !f.IsOrthogonal() {
+		t.Errorf("IsOrthogonal: got false for a reflection")
+	}
+}
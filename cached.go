@@ -0,0 +1,45 @@
+package mtransform
+
+// CachedTransform wraps a Transform and computes its inverse at most once per
+// Set, for code that maps many points back through the same transform. It is
+// not safe for concurrent use.
+type CachedTransform struct {
+	t   Transform
+	inv *Transform
+	err error
+}
+
+func NewCachedTransform(t Transform) *CachedTransform {
+	return &CachedTransform{t: t}
+}
+
+// Set replaces the transform and drops the cached inverse.
+func (c *CachedTransform) Set(t Transform) {
+	c.t = t
+	c.inv = nil
+	c.err = nil
+}
+
+func (c *CachedTransform) Get() Transform {
+	return c.t
+}
+
+func (c *CachedTransform) Apply(x float64, y float64) (float64, float64) {
+	return c.t.Apply(x, y)
+}
+
+func (c *CachedTransform) Inverse() (*Transform, error) {
+	if c.inv == nil && c.err == nil {
+		c.inv, c.err = c.t.Invert()
+	}
+	return c.inv, c.err
+}
+
+func (c *CachedTransform) InverseApply(x float64, y float64) (float64, float64, error) {
+	inv, err := c.Inverse()
+	if err != nil {
+		return 0, 0, err
+	}
+	X, Y := inv.Apply(x, y)
+	return X, Y, nil
+}
@@ -0,0 +1,29 @@
+package mtransform
+
+import "testing"
+
+func TestCachedTransform(t *testing.T) {
+	tr := NewTRS(3, -4, 0.6, 2, 0.5)
+	c := NewCachedTransform(*tr)
+	x, y := c.Apply(1, 2)
+	X, Y, err := c.InverseApply(x, y)
+	if err != nil || !near(X, 1) || !near(Y, 2) {
+		t.Errorf("InverseApply: got (%v, %v, %v), want (1, 2)", X, Y, err)
+	}
+	if X2, Y2, _ := tr.InverseApply(x, y); X2 != X || Y2 != Y {
+		t.Errorf("InverseApply: cached (%v, %v) differs from uncached (%v, %v)", X, Y, X2, Y2)
+	}
+
+	c.Set(*NewTRS(0, 0, 0, 2, 2))
+	if X, Y, _ := c.InverseApply(4, 6); X != 2 || Y != 3 {
+		t.Errorf("InverseApply after Set: got (%v, %v), want (2, 3)", X, Y)
+	}
+	c.Set(*NewTRS(0, 0, 0, 0, 2))
+	if _, _, err := c.InverseApply(4, 6); err != ErrNotInvertible {
+		t.Errorf("InverseApply of singular: got %v, want %v", err, ErrNotInvertible)
+	}
+	c.Set(Identity())
+	if X, Y, err := c.InverseApply(4, 6); err != nil || X != 4 || Y != 6 {
+		t.Errorf("InverseApply after singular: got (%v, %v, %v), want (4, 6)", X, Y, err)
+	}
+}
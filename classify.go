@@ -0,0 +1,76 @@
+package mtransform
+
+import (
+	"math"
+	"strconv"
+)
+
+// TransformKind is the most specific family a transform belongs to, from
+// KindIdentity up to KindProjective.
+type TransformKind int
+
+const (
+	KindIdentity TransformKind = iota
+	// KindTranslation has an identity linear block.
+	KindTranslation
+	// KindScale is an axis-aligned scale about the origin, possibly negative.
+	KindScale
+	// KindRotation is a proper rotation about the origin.
+	KindRotation
+	// KindRigid is a proper rotation followed by a translation.
+	KindRigid
+	// KindSimilarity is a uniform scale, rotation, optional reflection and
+	// translation.
+	KindSimilarity
+	// KindAffine has a bottom row of [0 0 1] but a linear part that scales
+	// the axes unequally or shears, so angles are not preserved.
+	KindAffine
+	// KindProjective has a bottom row other than [0 0 1].
+	KindProjective
+)
+
+var kindNames = [...]string{"identity", "translation", "scale", "rotation", "rigid", "similarity", "affine", "projective"}
+
+func (k TransformKind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return "TransformKind(" + strconv.Itoa(int(k)) + ")"
+	}
+	return kindNames[k]
+}
+
+// Classify is ClassifyTol with defaultEpsilon.
+func (t *Transform) Classify() TransformKind {
+	return t.ClassifyTol(defaultEpsilon)
+}
+
+// ClassifyTol returns the most specific kind of t, treating elements within
+// epsilon of the required value as exact.
+func (t *Transform) ClassifyTol(epsilon float64) TransformKind {
+	is := func(v, want float64) bool { return math.Abs(v-want) <= epsilon }
+	if !is(t[2][0], 0) || !is(t[2][1], 0) || !is(t[2][2], 1) {
+		return KindProjective
+	}
+	translated := !is(t[0][2], 0) || !is(t[1][2], 0)
+	diagonal := is(t[1][0], 0) && is(t[0][1], 0)
+	switch {
+	case diagonal && is(t[0][0], 1) && is(t[1][1], 1):
+		if translated {
+			return KindTranslation
+		}
+		return KindIdentity
+	case diagonal && !translated:
+		return KindScale
+	case t.IsOrthogonalTol(epsilon) && t.Determinant() > 0:
+		if translated {
+			return KindRigid
+		}
+		return KindRotation
+	}
+	col0 := t[0][0]*t[0][0] + t[1][0]*t[1][0]
+	col1 := t[0][1]*t[0][1] + t[1][1]*t[1][1]
+	dot := t[0][0]*t[0][1] + t[1][0]*t[1][1]
+	if col0 > 0 && math.Abs(col1-col0) <= epsilon*col0 && math.Abs(dot) <= epsilon*col0 {
+		return KindSimilarity
+	}
+	return KindAffine
+}
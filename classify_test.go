@@ -0,0 +1,40 @@
+package mtransform
+
+import (
+	"math"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	build := func(f func(t *Transform)) *Transform {
+		tr := NewTransform()
+		f(tr)
+		return tr
+	}
+	cases := []struct {
+		name string
+		t    *Transform
+		want TransformKind
+	}{
+		{"identity", NewTransform(), KindIdentity},
+		{"rotate and unrotate", build(func(t *Transform) { t.RotateOrigin(0.3); t.RotateOrigin(-0.3) }), KindIdentity},
+		{"translate", build(func(t *Transform) { t.Translate(3, -4) }), KindTranslation},
+		{"scale", build(func(t *Transform) { t.Scale(2, 5) }), KindScale},
+		{"reflect", build(func(t *Transform) { t.ReflectX() }), KindScale},
+		{"rotate", build(func(t *Transform) { t.RotateOrigin(math.Pi / 3) }), KindRotation},
+		{"rotate about point", build(func(t *Transform) { t.RotatePoint(1, 2, 3) }), KindRigid},
+		{"uniform scale and rotate", NewTRS(1, 2, 0.5, 3, 3), KindSimilarity},
+		{"reflected rotation", NewTRS(0, 0, 0.5, 1, -1), KindSimilarity},
+		{"scale and translate", NewTRS(1, 2, 0, 2, 3), KindAffine},
+		{"skew", build(func(t *Transform) { t.SkewX(0.2) }), KindAffine},
+		{"perspective", &Transform{{1, 0, 0}, {0, 1, 0}, {0.01, 0, 1}}, KindProjective},
+	}
+	for _, c := range cases {
+		if got := c.t.Classify(); got != c.want {
+			t.Errorf("Classify(%s): got %v, want %v", c.name, got, c.want)
+		}
+	}
+	if got := TransformKind(42).String(); got != "TransformKind(42)" {
+		t.Errorf("TransformKind(42).String(): got %q", got)
+	}
+}
@@ -0,0 +1,256 @@
+package mtransform
+
+import (
+	"errors"
+	"math"
+)
+
+// Decomposition describes an affine transform as
+// Translate·Rotate·ShearX·Scale, the order used by Compose. Shear is the tangent
+// of the SkewX angle. A reflection is carried by a negative ScaleY.
+type Decomposition struct {
+	TranslateX, TranslateY float64
+	Rotation               float64
+	Shear                  float64
+	ScaleX, ScaleY         float64
+}
+
+// Decompose factors the linear block with a QR decomposition: ScaleX is the
+// length of the mapped x axis and Rotation its angle. ScaleX is never negative,
+// so a reflection always ends up in ScaleY and Compose reproduces it exactly.
+// When the x axis collapses to zero the rotation is taken from the mapped y
+// axis instead. A rank-one block whose columns are parallel and non-zero has
+// no decomposition of this form; ValidateDecomposition reports that case.
+func (t *Transform) Decompose() Decomposition {
+	d := Decomposition{TranslateX: t[0][2], TranslateY: t[1][2]}
+	d.ScaleX = math.Hypot(t[0][0], t[1][0])
+	if d.ScaleX == 0 {
+		d.ScaleY = math.Hypot(t[0][1], t[1][1])
+		if d.ScaleY != 0 {
+			d.Rotation = math.Atan2(-t[0][1], t[1][1])
+		}
+		return d
+	}
+	d.Rotation = math.Atan2(t[1][0], t[0][0])
+	d.ScaleY = t.Determinant() / d.ScaleX
+	if d.ScaleY != 0 {
+		d.Shear = (t[0][0]*t[0][1] + t[1][0]*t[1][1]) / (d.ScaleX * d.ScaleY)
+	}
+	return d
+}
+
+// DecomposeAround is Decompose with rotation, shear and scale taken to pivot
+// about (cx, cy). The translation is what remains once the pivot is accounted
+// for, so the transform equals Translate(cx+TranslateX, cy+TranslateY)
+// followed by the linear part and Translate(-cx, -cy).
+func (t *Transform) DecomposeAround(cx, cy float64) Decomposition {
+	d := t.Decompose()
+	x, y := t.Apply(cx, cy)
+	d.TranslateX = x - cx
+	d.TranslateY = y - cy
+	return d
+}
+
+// HasReflection reports whether the decomposed transform flips orientation.
+func (d Decomposition) HasReflection() bool {
+	return d.ScaleX*d.ScaleY < 0
+}
+
+// ErrNotDecomposable is returned by ValidateDecomposition.
+var ErrNotDecomposable = errors.New("mtransform: Compose(Decompose()) does not reproduce the transform")
+
+// ValidateDecomposition checks that Compose(Decompose()) reproduces t. The
+// linear block and the translation are compared separately, each to within
+// defaultEpsilon relative to its own size, so a large translation cannot hide
+// an error in the linear block. It fails for projective transforms, non-finite
+// elements and rank-one blocks with parallel columns.
+func (t *Transform) ValidateDecomposition() error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	if !t.IsAffine() {
+		return ErrNotDecomposable
+	}
+	if !t.affineClose(Compose(t.Decompose())) {
+		return ErrNotDecomposable
+	}
+	return nil
+}
+
+// affineClose reports whether other matches the affine part of t, with the
+// linear blocks and the translations each compared to within defaultEpsilon
+// relative to the size of t's own.
+func (t *Transform) affineClose(other *Transform) bool {
+	linear := math.Sqrt(t[0][0]*t[0][0] + t[0][1]*t[0][1] + t[1][0]*t[1][0] + t[1][1]*t[1][1])
+	translation := math.Hypot(t[0][2], t[1][2])
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			if !(math.Abs(other[i][j]-t[i][j]) <= defaultEpsilon*linear) {
+				return false
+			}
+		}
+		if !(math.Abs(other[i][2]-t[i][2]) <= defaultEpsilon*translation) {
+			return false
+		}
+	}
+	return true
+}
+
+func Compose(d Decomposition) *Transform {
+	t := NewTransform()
+	t.Translate(d.TranslateX, d.TranslateY)
+	t.RotateOrigin(d.Rotation)
+	t.MultiplyWith(Transform{{1, d.Shear, 0}, {0, 1, 0}, {0, 0, 1}})
+	t.Scale(d.ScaleX, d.ScaleY)
+	return t
+}
+
+func (t *Transform) GetTranslation() (float64, float64) {
+	return t[0][2], t[1][2]
+}
+
+// LinearPart returns a copy of t with the translation set to zero, for mapping
+// directions as a Transform that can be composed further.
+func (t *Transform) LinearPart() *Transform {
+	l := t.Clone()
+	l.SetTranslation(0, 0)
+	return l
+}
+
+// TranslationPart returns the pure translation by t's translation column. For
+// an affine t, t = TranslationPart·LinearPart: the linear part is applied to
+// points first, then the translation. The opposite order is not t in general.
+func (t *Transform) TranslationPart() *Transform {
+	p := NewTransform()
+	p.SetTranslation(t[0][2], t[1][2])
+	return p
+}
+
+// GetScale returns the same values as GetScaleSigned.
+//
+// Deprecated: Use GetScaleSigned, or GetScaleMagnitude when the reflection
+// sign is not wanted.
+func (t *Transform) GetScale() (float64, float64) {
+	return t.GetScaleSigned()
+}
+
+// GetScaleSigned returns the scale factors from Decompose. A reflection is
+// encoded as a negative y factor; putting the sign on y rather than x is a
+// convention, not something the matrix determines.
+func (t *Transform) GetScaleSigned() (float64, float64) {
+	d := t.Decompose()
+	return d.ScaleX, d.ScaleY
+}
+
+// GetScaleMagnitude returns the absolute scale factors from Decompose.
+func (t *Transform) GetScaleMagnitude() (float64, float64) {
+	sx, sy := t.GetScaleSigned()
+	return math.Abs(sx), math.Abs(sy)
+}
+
+// ClampScale clamps the magnitude of both scale factors into
+// [minScale, maxScale], keeping their signs, rotation, shear and translation.
+func (t *Transform) ClampScale(minScale, maxScale float64) {
+	d := t.Decompose()
+	d.ScaleX = math.Copysign(math.Min(math.Max(math.Abs(d.ScaleX), minScale), maxScale), d.ScaleX)
+	d.ScaleY = math.Copysign(math.Min(math.Max(math.Abs(d.ScaleY), minScale), maxScale), d.ScaleY)
+	*t = *Compose(d)
+}
+
+// SetTranslation replaces the translation column, leaving the linear block
+// alone.
+func (t *Transform) SetTranslation(tx, ty float64) {
+	t[0][2], t[1][2] = tx, ty
+}
+
+// SetRotation replaces the Decompose rotation, keeping shear, scale and
+// translation.
+func (t *Transform) SetRotation(angle float64) {
+	d := t.Decompose()
+	d.Rotation = angle
+	*t = *Compose(d)
+}
+
+// SetScale replaces the scale factors as reported by GetScaleSigned, keeping
+// rotation, shear and translation. A negative sy sets a reflection.
+func (t *Transform) SetScale(sx, sy float64) {
+	d := t.Decompose()
+	d.ScaleX, d.ScaleY = sx, sy
+	*t = *Compose(d)
+}
+
+var (
+	// ErrLengthMismatch is returned when two slices that should pair up
+	// element for element have different lengths.
+	ErrLengthMismatch = errors.New("mtransform: slice lengths differ")
+	// ErrNonPositiveWeight is returned by WeightedAverage when the weights
+	// do not sum to a positive total.
+	ErrNonPositiveWeight = errors.New("mtransform: total weight must be positive")
+)
+
+// WeightedAverage blends transforms in Decompose space: translation, shear and
+// scale are averaged linearly and rotation as a weighted circular mean. It
+// returns ErrLengthMismatch or ErrNonPositiveWeight for unusable weights.
+func WeightedAverage(transforms []Transform, weights []float64) (Transform, error) {
+	if len(transforms) != len(weights) {
+		return Transform{}, ErrLengthMismatch
+	}
+	var total, sin, cos float64
+	var avg Decomposition
+	for i := range transforms {
+		w := weights[i]
+		d := transforms[i].Decompose()
+		total += w
+		sin += w * math.Sin(d.Rotation)
+		cos += w * math.Cos(d.Rotation)
+		avg.TranslateX += w * d.TranslateX
+		avg.TranslateY += w * d.TranslateY
+		avg.Shear += w * d.Shear
+		avg.ScaleX += w * d.ScaleX
+		avg.ScaleY += w * d.ScaleY
+	}
+	if total <= 0 {
+		return Transform{}, ErrNonPositiveWeight
+	}
+	avg.Rotation = math.Atan2(sin, cos)
+	avg.TranslateX /= total
+	avg.TranslateY /= total
+	avg.Shear /= total
+	avg.ScaleX /= total
+	avg.ScaleY /= total
+	return *Compose(avg), nil
+}
+
+// GetShearXY recovers shx and shy from a linear block built by Shear, possibly
+// followed by Scale, with no rotation. A general matrix cannot separate shear
+// from rotation uniquely, so for other transforms the result is meaningless;
+// use Decompose instead.
+func (t *Transform) GetShearXY() (shx, shy float64) {
+	if t[1][1] != 0 {
+		shx = t[0][1] / t[1][1]
+	}
+	if t[0][0] != 0 {
+		shy = t[1][0] / t[0][0]
+	}
+	return shx, shy
+}
+
+// SnapRotation rounds the rotation to the nearest multiple of stepDegrees,
+// keeping scale and shear. The correction pivots about the transform's fixed
+// point, or its mapped origin when there is none, so the content does not
+// jump. A step that is not positive does nothing.
+func (t *Transform) SnapRotation(stepDegrees float64) {
+	if stepDegrees <= 0 {
+		return
+	}
+	rotation := t.GetRotation()
+	delta := radians(math.Round(degrees(rotation)/stepDegrees)*stepDegrees) - rotation
+	p, err := t.FixedPoint()
+	if err != nil {
+		p = t.ApplyToPoint(Point{})
+	}
+	r := NewTransform()
+	r.RotatePoint(delta, p.X, p.Y)
+	r.MultiplyWith(*t)
+	*t = *r
+}
@@ -0,0 +1,265 @@
+package mtransform
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDecompose(t *testing.T) {
+	tr := NewTransform()
+	tr.Translate(3, -4)
+	tr.RotateOrigin(0.8)
+	tr.SkewX(0.3)
+	tr.Scale(2, -0.5)
+	d := tr.Decompose()
+	if !near(d.Rotation, 0.8) || !near(d.ScaleX, 2) || !near(d.ScaleY, -0.5) ||
+		d.TranslateX != 3 || d.TranslateY != -4 {
+		t.Errorf("Decompose: got %+v", d)
+	}
+	if got := Compose(d); !nearTransform(got, tr) {
+		t.Errorf("Compose(Decompose()): got %v, want %v", *got, *tr)
+	}
+	if sx, sy := tr.GetScaleSigned(); !near(sx, 2) || !near(sy, -0.5) {
+		t.Errorf("GetScaleSigned: got %v, %v, want 2, -0.5", sx, sy)
+	}
+}
+
+func TestClampScale(t *testing.T) {
+	tr := NewTransform()
+	tr.Translate(7, 8)
+	tr.RotateOrigin(-1.2)
+	tr.Scale(0.0001, 1000)
+	tr.ClampScale(0.5, 4)
+	if sx, sy := tr.GetScaleSigned(); !near(sx, 0.5) || !near(sy, 4) {
+		t.Errorf("ClampScale: got scale %v, %v, want 0.5, 4", sx, sy)
+	}
+	if x, y := tr.GetTranslation(); x != 7 || y != 8 {
+		t.Errorf("ClampScale: got translation %v, %v, want 7, 8", x, y)
+	}
+	if r := tr.GetRotation(); !near(r, -1.2) {
+		t.Errorf("ClampScale: got rotation %v, want -1.2", r)
+	}
+}
+
+func TestSetComponents(t *testing.T) {
+	tr := NewTransform()
+	tr.Translate(3, -4)
+	tr.RotateOrigin(0.8)
+	tr.SkewX(0.3)
+	tr.Scale(2, 5)
+	before := tr.Decompose()
+
+	tr.SetRotation(-2)
+	if got := tr.GetRotation(); !near(got, -2) {
+		t.Errorf("SetRotation: GetRotation got %v, want -2", got)
+	}
+	after := tr.Decompose()
+	if !near(after.ScaleX, 2) || !near(after.ScaleY, 5) || !near(after.Shear, before.Shear) ||
+		after.TranslateX != 3 || after.TranslateY != -4 {
+		t.Errorf("SetRotation: got %+v, want only the rotation of %+v changed", after, before)
+	}
+
+	tr.SetScale(0.5, -1)
+	after = tr.Decompose()
+	if !near(after.ScaleX, 0.5) || !near(after.ScaleY, -1) || !near(after.Rotation, -2) ||
+		!near(after.Shear, before.Shear) || after.TranslateX != 3 || after.TranslateY != -4 {
+		t.Errorf("SetScale: got %+v", after)
+	}
+
+	linear := [2][2]float64{{tr[0][0], tr[0][1]}, {tr[1][0], tr[1][1]}}
+	tr.SetTranslation(10, 20)
+	if x, y := tr.GetTranslation(); x != 10 || y != 20 {
+		t.Errorf("SetTranslation: got %v, %v, want 10, 20", x, y)
+	}
+	if tr[0][0] != linear[0][0] || tr[0][1] != linear[0][1] || tr[1][0] != linear[1][0] || tr[1][1] != linear[1][1] {
+		t.Errorf("SetTranslation changed the linear block: %v", *tr)
+	}
+}
+
+func TestLinearPart(t *testing.T) {
+	tr := NewTRS(3, -4, 0.8, 2, -0.5)
+	tr.SkewX(0.3)
+	l := tr.LinearPart()
+	for _, p := range []Point{{0, 0}, {1, 2}, {-3, 0.5}} {
+		x, y := tr.ApplyVector(p.X, p.Y)
+		if got := l.ApplyToPoint(p); got != (Point{x, y}) {
+			t.Errorf("LinearPart applied to %v: got %v, want %v", p, got, Point{x, y})
+		}
+	}
+	if tr[0][2] != 3 || tr[1][2] != -4 {
+		t.Errorf("LinearPart modified the receiver: %v", *tr)
+	}
+}
+
+func TestTranslationPart(t *testing.T) {
+	tr := NewTRS(3, -4, 0.8, 2, -0.5)
+	tr.SkewX(0.3)
+	p := tr.TranslationPart()
+	if x, y := p.Apply(1, 2); x != 4 || y != -2 {
+		t.Errorf("TranslationPart: (1, 2) maps to (%v, %v), want (4, -2)", x, y)
+	}
+	p.MultiplyWith(*tr.LinearPart())
+	if *p != *tr {
+		t.Errorf("TranslationPart·LinearPart: got %v, want %v", *p, *tr)
+	}
+	q := tr.LinearPart()
+	q.MultiplyWith(*tr.TranslationPart())
+	if q.AffineEqualsTol(tr, 1e-9) {
+		t.Errorf("LinearPart·TranslationPart unexpectedly equals %v", *tr)
+	}
+}
+
+func TestDecomposeReflection(t *testing.T) {
+	r := NewTransform()
+	r.ReflectX()
+	d := r.Decompose()
+	if !d.HasReflection() {
+		t.Errorf("Decompose of ReflectX: %+v reports no reflection", d)
+	}
+	if got := Compose(d); !got.Equals(r) {
+		t.Errorf("Compose(Decompose()) of ReflectX: got %v, want %v", *got, *r)
+	}
+
+	r = NewTransform()
+	r.ReflectY()
+	r.RotateOrigin(0.5)
+	d = r.Decompose()
+	if !d.HasReflection() || d.ScaleX < 0 {
+		t.Errorf("Decompose of ReflectY: got %+v, want reflection on ScaleY", d)
+	}
+	if got := Compose(d); !nearTransform(got, r) {
+		t.Errorf("Compose(Decompose()) of ReflectY: got %v, want %v", *got, *r)
+	}
+	if d := NewTRS(1, 2, 0.3, 2, 3).Decompose(); d.HasReflection() {
+		t.Errorf("Decompose: %+v reports a reflection", d)
+	}
+}
+
+func TestDecomposeRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(369))
+	param := func() float64 {
+		// Spread magnitudes over several decades, both signs.
+		return (rng.Float64()*2 - 1) * math.Pow(10, rng.Float64()*6-3)
+	}
+	for i := 0; i < 2000; i++ {
+		tr := NewFromComponents(param(), param(), param(), param(), param()*1e9, param())
+		if err := tr.ValidateDecomposition(); err != nil {
+			d := tr.Decompose()
+			t.Fatalf("Decompose of %v: %v (got %+v)", *tr, err, d)
+		}
+	}
+
+	families := map[string]*Transform{
+		"heavy shear with reflection": NewFromComponents(1, 0, 1e6, -1, 0, 0),
+		"zero x axis":                 NewFromComponents(0, 0, 3, -4, 5, 6),
+		"zero x axis, reflected":      NewFromComponents(0, 0, -2, -1, 0, 0),
+		"tiny scale":                  NewFromComponents(1e-9, 2e-9, -3e-9, 1e-9, 0, 0),
+		"large translation":           NewFromComponents(0.5, 2, -1, 0.25, 1e9, -1e9),
+		"zero linear block":           NewFromComponents(0, 0, 0, 0, 7, 8),
+	}
+	for name, tr := range families {
+		if err := tr.ValidateDecomposition(); err != nil {
+			t.Errorf("ValidateDecomposition(%s): %v, got %v from %+v", name, err, *Compose(tr.Decompose()), tr.Decompose())
+		}
+	}
+
+	for _, tx := range []float64{0, 1e5, 1e12} {
+		parallel := NewFromComponents(1, 2, 3, 6, tx, 0)
+		if err := parallel.ValidateDecomposition(); err != ErrNotDecomposable {
+			t.Errorf("ValidateDecomposition of parallel columns with tx=%v: got %v, want %v", tx, err, ErrNotDecomposable)
+		}
+	}
+	projective := Transform{{1, 0, 0}, {0, 1, 0}, {0.5, 0, 1}}
+	if err := projective.ValidateDecomposition(); err != ErrNotDecomposable {
+		t.Errorf("ValidateDecomposition of projective: got %v, want %v", err, ErrNotDecomposable)
+	}
+}
+
+func TestWeightedAverage(t *testing.T) {
+	ts := []Transform{*NewTRS(0, 0, 3, 1, 1), *NewTRS(10, 20, -3, 3, 5)}
+	got, err := WeightedAverage(ts, []float64{1, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := NewTRS(5, 10, math.Pi, 2, 3)
+	if !nearTransform(&got, want) {
+		t.Errorf("WeightedAverage across ±π: got %v, want %v", got, *want)
+	}
+	got, _ = WeightedAverage(ts, []float64{3, 1})
+	if x, y := got.GetTranslation(); !near(x, 2.5) || !near(y, 5) {
+		t.Errorf("WeightedAverage 3:1: got translation %v, %v, want 2.5, 5", x, y)
+	}
+	if _, err := WeightedAverage(ts, []float64{1}); err != ErrLengthMismatch {
+		t.Errorf("WeightedAverage with mismatched lengths: got %v, want %v", err, ErrLengthMismatch)
+	}
+	if _, err := WeightedAverage(ts, []float64{1, -1}); err != ErrNonPositiveWeight {
+		t.Errorf("WeightedAverage with zero total weight: got %v, want %v", err, ErrNonPositiveWeight)
+	}
+}
+
+func TestGetScaleReflected(t *testing.T) {
+	tr := NewTRS(1, 2, 0.7, 3, 2)
+	tr.ReflectY()
+	if sx, sy := tr.GetScaleSigned(); !near(sx, 3) || !near(sy, -2) {
+		t.Errorf("GetScaleSigned: got %v, %v, want 3, -2", sx, sy)
+	}
+	if sx, sy := tr.GetScaleMagnitude(); !near(sx, 3) || !near(sy, 2) {
+		t.Errorf("GetScaleMagnitude: got %v, %v, want 3, 2", sx, sy)
+	}
+}
+
+func TestGetShearXY(t *testing.T) {
+	tr := NewTransform()
+	tr.Translate(4, 5)
+	tr.Shear(0.3, 0.2)
+	if shx, shy := tr.GetShearXY(); !near(shx, 0.3) || !near(shy, 0.2) {
+		t.Errorf("GetShearXY of Shear(0.3, 0.2): got %v, %v", shx, shy)
+	}
+	tr.Scale(2, -3)
+	if shx, shy := tr.GetShearXY(); !near(shx, 0.3) || !near(shy, 0.2) {
+		t.Errorf("GetShearXY of scaled Shear(0.3, 0.2): got %v, %v", shx, shy)
+	}
+	if x, y := NewTransform().GetShearXY(); x != 0 || y != 0 {
+		t.Errorf("GetShearXY of identity: got %v, %v", x, y)
+	}
+}
+
+func TestDecomposeAround(t *testing.T) {
+	tr := NewTransform()
+	tr.RotatePoint(0.9, 3, -2)
+	d := tr.DecomposeAround(3, -2)
+	if !near(d.Rotation, 0.9) || !near(d.TranslateX, 0) || !near(d.TranslateY, 0) ||
+		!near(d.ScaleX, 1) || !near(d.ScaleY, 1) || !near(d.Shear, 0) {
+		t.Errorf("DecomposeAround pivot: got %+v, want pure rotation 0.9", d)
+	}
+	tr = NewTransform()
+	tr.Translate(5, 1)
+	tr.ScaleAroundPoint(2, 3, 3, -2)
+	d = tr.DecomposeAround(3, -2)
+	if !near(d.TranslateX, 5) || !near(d.TranslateY, 1) || !near(d.ScaleX, 2) || !near(d.ScaleY, 3) {
+		t.Errorf("DecomposeAround scaled: got %+v", d)
+	}
+}
+
+func TestSnapRotation(t *testing.T) {
+	tr := NewTransform()
+	tr.RotatePoint(radians(47), 3, 4)
+	tr.ScaleAroundPoint(2, 1.5, 3, 4)
+	tr.SnapRotation(15)
+	if got := tr.GetRotationDeg(); !near(got, 45) {
+		t.Errorf("SnapRotation(15) of 47°: got %v°, want 45°", got)
+	}
+	if p := tr.ApplyToPoint(Point{3, 4}); !near(p.X, 3) || !near(p.Y, 4) {
+		t.Errorf("SnapRotation moved the pivot to %v", p)
+	}
+	if sx, sy := tr.GetScaleSigned(); !near(sx, 2) || !near(sy, 1.5) {
+		t.Errorf("SnapRotation changed the scale to %v, %v", sx, sy)
+	}
+
+	tr = NewTRS(10, 20, radians(-98), 1, 1)
+	tr.SnapRotation(45)
+	if got := tr.GetRotationDeg(); !near(got, -90) {
+		t.Errorf("SnapRotation(45) of -98°: got %v°, want -90°", got)
+	}
+}
@@ -0,0 +1,63 @@
+package mtransform
+
+import "errors"
+
+// Zero returns the all-zero matrix, the additive identity for Add.
+func Zero() Transform {
+	return Transform{}
+}
+
+// Add returns the element-wise sum of t and other.
+func (t *Transform) Add(other *Transform) Transform {
+	r := *t
+	r.AddInPlace(other)
+	return r
+}
+
+func (t *Transform) AddInPlace(other *Transform) {
+	for i := range t {
+		for j := range t[i] {
+			t[i][j] += other[i][j]
+		}
+	}
+}
+
+// ScaleMatrix returns t with every element multiplied by s. Unlike Scale it
+// scales the matrix itself, not the geometry.
+func (t *Transform) ScaleMatrix(s float64) Transform {
+	var r Transform
+	for i := range t {
+		for j := range t[i] {
+			r[i][j] = t[i][j] * s
+		}
+	}
+	return r
+}
+
+// Sub returns the element-wise difference t - other.
+func (t *Transform) Sub(other *Transform) Transform {
+	neg := other.ScaleMatrix(-1)
+	return t.Add(&neg)
+}
+
+// ErrNoMatrices is returned by LinearCombination when given nothing to combine.
+var ErrNoMatrices = errors.New("mtransform: no matrices to combine")
+
+// LinearCombination returns Σ coeffs[i]*mats[i] computed element-wise. It
+// operates purely in matrix space; use WeightedAverage to blend geometry. It
+// returns ErrLengthMismatch when the slices differ in length and ErrNoMatrices
+// when they are empty.
+func LinearCombination(coeffs []float64, mats []Transform) (Transform, error) {
+	var r Transform
+	if len(coeffs) != len(mats) {
+		return r, ErrLengthMismatch
+	}
+	if len(mats) == 0 {
+		return r, ErrNoMatrices
+	}
+	for k := range mats {
+		s := mats[k].ScaleMatrix(coeffs[k])
+		r.AddInPlace(&s)
+	}
+	return r, nil
+}
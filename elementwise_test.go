@@ -0,0 +1,49 @@
+package mtransform
+
+import "testing"
+
+func TestElementwise(t *testing.T) {
+	a := Transform{{1, 2, 3}, {-1, -2, -3}, {4, 5, 6}}
+	z := Zero()
+	if got := a.Add(&z); got != a {
+		t.Errorf("Add(Zero()): got %v, want %v", got, a)
+	}
+	if got := a.ScaleMatrix(0); got != Zero() {
+		t.Errorf("ScaleMatrix(0): got %v, want zero", got)
+	}
+	want := Transform{{2, 4, 6}, {-2, -4, -6}, {8, 10, 12}}
+	if got := a.ScaleMatrix(2); got != want {
+		t.Errorf("ScaleMatrix(2): got %v, want %v", got, want)
+	}
+	b := a
+	b.AddInPlace(&a)
+	if b != want {
+		t.Errorf("AddInPlace: got %v, want %v", b, want)
+	}
+}
+
+func TestSub(t *testing.T) {
+	a := Transform{{1, 2, 3}, {-1, -2, -3}, {4, 5, 6}}
+	if got := a.Sub(&a); got != Zero() {
+		t.Errorf("Sub: got %v, want zero", got)
+	}
+}
+
+func TestLinearCombination(t *testing.T) {
+	a := Transform{{1, 0, 2}, {0, 1, 4}, {0, 0, 1}}
+	b := Transform{{3, 1, 0}, {1, 3, -2}, {0, 0, 1}}
+	got, err := LinearCombination([]float64{2, -1}, []Transform{a, b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Transform{{-1, -1, 4}, {-1, -1, 10}, {0, 0, 1}}
+	if got != want {
+		t.Errorf("LinearCombination: got %v, want %v", got, want)
+	}
+	if _, err := LinearCombination([]float64{1}, []Transform{a, b}); err != ErrLengthMismatch {
+		t.Errorf("LinearCombination with mismatched lengths: got %v, want %v", err, ErrLengthMismatch)
+	}
+	if _, err := LinearCombination(nil, nil); err != ErrNoMatrices {
+		t.Errorf("LinearCombination with no matrices: got %v, want %v", err, ErrNoMatrices)
+	}
+}
@@ -0,0 +1,106 @@
+package mtransform
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+)
+
+type affineJSON struct {
+	A  float64 `json:"a"`
+	B  float64 `json:"b"`
+	C  float64 `json:"c"`
+	D  float64 `json:"d"`
+	TX float64 `json:"tx"`
+	TY float64 `json:"ty"`
+}
+
+// MarshalJSON encodes the six affine parameters in the SVG matrix(a,b,c,d,e,f)
+// convention, with e and f named tx and ty.
+func (t Transform) MarshalJSON() ([]byte, error) {
+	return json.Marshal(affineJSON{
+		A: t[0][0], B: t[1][0],
+		C: t[0][1], D: t[1][1],
+		TX: t[0][2], TY: t[1][2],
+	})
+}
+
+// UnmarshalJSON decodes the object written by MarshalJSON. Missing fields take
+// their identity values.
+func (t *Transform) UnmarshalJSON(data []byte) error {
+	v := affineJSON{A: 1, D: 1}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*t = Transform{
+		{v.A, v.C, v.TX},
+		{v.B, v.D, v.TY},
+		{0, 0, 1},
+	}
+	return nil
+}
+
+const binarySize = 48
+
+// ErrInvalidEncoding is returned when binary or gob data has the wrong length.
+var ErrInvalidEncoding = errors.New("mtransform: encoded data has the wrong length")
+
+// AppendBinary appends a, b, c, d, e, f to dst as little-endian IEEE-754
+// doubles, binarySize bytes in total.
+func (t *Transform) AppendBinary(dst []byte) []byte {
+	for _, v := range [6]float64{t[0][0], t[1][0], t[0][1], t[1][1], t[0][2], t[1][2]} {
+		dst = binary.LittleEndian.AppendUint64(dst, math.Float64bits(v))
+	}
+	return dst
+}
+
+// MarshalBinary has a value receiver so Transform values satisfy
+// encoding.BinaryMarshaler.
+func (t Transform) MarshalBinary() ([]byte, error) {
+	return t.AppendBinary(make([]byte, 0, binarySize)), nil
+}
+
+// UnmarshalBinary decodes exactly binarySize bytes; any other length returns
+// ErrInvalidEncoding.
+func (t *Transform) UnmarshalBinary(data []byte) error {
+	if len(data) != binarySize {
+		return ErrInvalidEncoding
+	}
+	var v [6]float64
+	for i := range v {
+		v[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:]))
+	}
+	*t = Transform{
+		{v[0], v[2], v[4]},
+		{v[1], v[3], v[5]},
+		{0, 0, 1},
+	}
+	return nil
+}
+
+const gobVersion = 1
+
+// ErrUnsupportedVersion is returned by GobDecode for data written by an
+// unknown encoding version.
+var ErrUnsupportedVersion = errors.New("mtransform: unsupported gob encoding version")
+
+// GobEncode writes a version byte followed by the AppendBinary layout. It has
+// a value receiver, like MarshalJSON, so Transform values held in structs and
+// maps encode too.
+func (t Transform) GobEncode() ([]byte, error) {
+	return t.AppendBinary([]byte{gobVersion}), nil
+}
+
+// GobDecode reads the data written by GobEncode. It returns
+// ErrUnsupportedVersion for an unknown version byte and ErrInvalidEncoding for
+// a payload of the wrong length.
+func (t *Transform) GobDecode(data []byte) error {
+	if len(data) == 0 || data[0] != gobVersion {
+		return ErrUnsupportedVersion
+	}
+	if len(data) != 1+binarySize {
+		return ErrInvalidEncoding
+	}
+	return t.UnmarshalBinary(data[1:])
+}
@@ -0,0 +1,113 @@
+package mtransform
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	want := NewTransform()
+	want.Translate(10.5, -3)
+	want.RotateOrigin(0.3)
+	want.Scale(2, 0.25)
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Transform
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equals(want) {
+		t.Errorf("JSON round trip: got %v, want %v", got, *want)
+	}
+
+	data, _ = json.Marshal(Transform{{1, 3, 5}, {2, 4, 6}, {0, 0, 1}})
+	if string(data) != `{"a":1,"b":2,"c":3,"d":4,"tx":5,"ty":6}` {
+		t.Errorf("MarshalJSON: got %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"tx":7}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Transform{{1, 0, 7}, {0, 1, 0}, {0, 0, 1}}); got != want {
+		t.Errorf("UnmarshalJSON with missing fields: got %v, want %v", got, want)
+	}
+}
+
+func TestGob(t *testing.T) {
+	want := NewTransform()
+	want.RotatePoint(1.2, 4, 5)
+	want.Scale(-1, 3)
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(want); err != nil {
+		t.Fatal(err)
+	}
+	var got Transform
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equals(want) {
+		t.Errorf("gob round trip: got %v, want %v", got, *want)
+	}
+
+	type holder struct {
+		T Transform
+		M map[string]Transform
+	}
+	in := holder{T: *want, M: map[string]Transform{"id": Identity(), "t": *want}}
+	buf.Reset()
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("gob Encode of a struct holding Transform values: %v", err)
+	}
+	var out holder
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.T != in.T || len(out.M) != 2 || out.M["id"] != in.M["id"] || out.M["t"] != in.M["t"] {
+		t.Errorf("gob round trip of values: got %v, want %v", out, in)
+	}
+
+	data, _ := want.GobEncode()
+	data[0] = 2
+	if err := got.GobDecode(data); err != ErrUnsupportedVersion {
+		t.Errorf("GobDecode of an unknown version: got %v, want %v", err, ErrUnsupportedVersion)
+	}
+	data[0] = gobVersion
+	if err := got.GobDecode(data[:40]); err != ErrInvalidEncoding {
+		t.Errorf("GobDecode of a short payload: got %v, want %v", err, ErrInvalidEncoding)
+	}
+}
+
+func TestBinary(t *testing.T) {
+	want := NewTransform()
+	want.Translate(-7, 0.125)
+	want.SkewY(0.2)
+	data, err := want.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 48 {
+		t.Errorf("MarshalBinary: got %d bytes, want 48", len(data))
+	}
+	var got Transform
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equals(want) {
+		t.Errorf("binary round trip: got %v, want %v", got, *want)
+	}
+	if err := got.UnmarshalBinary(data[:47]); err != ErrInvalidEncoding {
+		t.Errorf("UnmarshalBinary of a short buffer: got %v, want %v", err, ErrInvalidEncoding)
+	}
+	if err := got.UnmarshalBinary(append(data, make([]byte, 52)...)); err != ErrInvalidEncoding {
+		t.Errorf("UnmarshalBinary of a 100-byte buffer: got %v, want %v", err, ErrInvalidEncoding)
+	}
+	var m encoding.BinaryMarshaler = *want
+	if value, _ := m.MarshalBinary(); !bytes.Equal(value, data) {
+		t.Errorf("MarshalBinary of a value: got %v, want %v", value, data)
+	}
+}
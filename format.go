@@ -0,0 +1,134 @@
+package mtransform
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// String returns the compact single-line form, [[a c e] [b d f] [0 0 1]].
+func (t Transform) String() string {
+	return t.compact(t.elements('g', -1))
+}
+
+// Format implements fmt.Formatter. %v and %s print the compact String form and
+// %+v prints an aligned three-line grid and %#v prints GoString. A precision
+// sets the number of decimals, so %.3v prints every element with three
+// decimals. %e, %f and %g format each element with that verb.
+func (t Transform) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprint(f, t.GoString())
+		return
+	}
+	prec, ok := f.Precision()
+	if !ok {
+		prec = -1
+	}
+	var e [3][3]string
+	switch verb {
+	case 'v', 's':
+		if ok {
+			e = t.elements('f', prec)
+		} else {
+			e = t.elements('g', prec)
+		}
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		e = t.elements(byte(verb), prec)
+	default:
+		fmt.Fprintf(f, "%%!%c(mtransform.Transform=%s)", verb, t.String())
+		return
+	}
+	if verb == 'v' && f.Flag('+') {
+		fmt.Fprint(f, t.grid(e))
+		return
+	}
+	fmt.Fprint(f, t.compact(e))
+}
+
+// GoString implements fmt.GoStringer with a composite literal that compiles
+// back to the same transform, such as
+// mtransform.Transform{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}.
+func (t Transform) GoString() string {
+	e := t.elements('g', -1)
+	rows := make([]string, 3)
+	for i := range e {
+		for j, s := range e[i] {
+			switch s {
+			case "NaN":
+				e[i][j] = "math.NaN()"
+			case "+Inf":
+				e[i][j] = "math.Inf(1)"
+			case "-Inf":
+				e[i][j] = "math.Inf(-1)"
+			}
+		}
+		rows[i] = "{" + strings.Join(e[i][:], ", ") + "}"
+	}
+	return "mtransform.Transform{" + strings.Join(rows, ", ") + "}"
+}
+
+func (t *Transform) elements(verb byte, prec int) [3][3]string {
+	var e [3][3]string
+	for i := range t {
+		for j := range t[i] {
+			e[i][j] = strconv.FormatFloat(t[i][j], verb, prec, 64)
+		}
+	}
+	return e
+}
+
+func (t *Transform) compact(e [3][3]string) string {
+	rows := make([]string, 3)
+	for i := range e {
+		rows[i] = "[" + strings.Join(e[i][:], " ") + "]"
+	}
+	return "[" + strings.Join(rows, " ") + "]"
+}
+
+func (t *Transform) grid(e [3][3]string) string {
+	var width [3]int
+	for i := range e {
+		for j := range e[i] {
+			width[j] = max(width[j], len(e[i][j]))
+		}
+	}
+	var b strings.Builder
+	for i := range e {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteByte('[')
+		for j := range e[i] {
+			if j > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(strings.Repeat(" ", width[j]-len(e[i][j])))
+			b.WriteString(e[i][j])
+		}
+		b.WriteByte(']')
+	}
+	return b.String()
+}
+
+// Diff describes the elements that differ between t and other, one per line
+// as "[i][j]: <t value> <other value> (delta <other - t>)", for reading test
+// failures. It returns "" when every element compares equal.
+func (t *Transform) Diff(other *Transform) string {
+	return t.DiffTol(other, 0)
+}
+
+// DiffTol is Diff ignoring elements that differ by at most epsilon.
+func (t *Transform) DiffTol(other *Transform, epsilon float64) string {
+	var lines []string
+	for i := range t {
+		for j := range t[i] {
+			a, b := t[i][j], other[i][j]
+			if a == b || math.Abs(a-b) <= epsilon {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("[%d][%d]: %v %v (delta %v)", i, j, a, b, b-a))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
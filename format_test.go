@@ -0,0 +1,102 @@
+package mtransform
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tr := Transform{{1, 0, 12.5}, {0, -1, 3}, {0, 0, 1}}
+	if got, want := tr.String(), "[[1 0 12.5] [0 -1 3] [0 0 1]]"; got != want {
+		t.Errorf("String: got %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%v", tr), fmt.Sprint([3][3]float64(tr)); got != want {
+		t.Errorf("%%v: got %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%v", &tr), tr.String(); got != want {
+		t.Errorf("%%v of pointer: got %q, want %q", got, want)
+	}
+	want := "[1  0 12.5]\n[0 -1    3]\n[0  0    1]"
+	if got := fmt.Sprintf("%+v", tr); got != want {
+		t.Errorf("%%+v: got\n%s\nwant\n%s", got, want)
+	}
+	third := Transform{{1.0 / 3, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	if got, want := fmt.Sprintf("%.4v", third), "[[0.3333 0.0000 0.0000] [0.0000 1.0000 0.0000] [0.0000 0.0000 1.0000]]"; got != want {
+		t.Errorf("%%.4v: got %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%d", tr), "%!d(mtransform.Transform=[[1 0 12.5] [0 -1 3] [0 0 1]])"; got != want {
+		t.Errorf("%%d: got %q, want %q", got, want)
+	}
+}
+
+func TestGoString(t *testing.T) {
+	tr := Transform{{1.0 / 3, -2, 1e20}, {0, -0.5, 3}, {0, 0, 1}}
+	src := fmt.Sprintf("%#v", tr)
+	if src != tr.GoString() {
+		t.Errorf("%%#v: got %q, want %q", src, tr.GoString())
+	}
+	expr, err := parser.ParseExpr(src)
+	if err != nil {
+		t.Fatalf("GoString %q does not parse: %v", src, err)
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok || types.ExprString(lit.Type) != "mtransform.Transform" || len(lit.Elts) != 3 {
+		t.Fatalf("GoString %q: not a mtransform.Transform literal", src)
+	}
+	var got Transform
+	for i, row := range lit.Elts {
+		elts := row.(*ast.CompositeLit).Elts
+		if len(elts) != 3 {
+			t.Fatalf("GoString %q: row %d has %d elements", src, i, len(elts))
+		}
+		for j, e := range elts {
+			sign := 1.0
+			if u, ok := e.(*ast.UnaryExpr); ok && u.Op == token.SUB {
+				sign, e = -1, u.X
+			}
+			v, err := strconv.ParseFloat(e.(*ast.BasicLit).Value, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got[i][j] = sign * v
+		}
+	}
+	if got != tr {
+		t.Errorf("GoString round trip: got %v, want %v", got, tr)
+	}
+
+	nan := Transform{{math.NaN(), math.Inf(1), math.Inf(-1)}, {0, 1, 0}, {0, 0, 1}}
+	want := "mtransform.Transform{{math.NaN(), math.Inf(1), math.Inf(-1)}, {0, 1, 0}, {0, 0, 1}}"
+	if got := nan.GoString(); got != want {
+		t.Errorf("GoString non-finite: got %q, want %q", got, want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := NewTRS(5, 6, 0, 2, 3)
+	b := *a
+	if got := a.Diff(&b); got != "" {
+		t.Errorf("Diff of equal: got %q", got)
+	}
+	b[1][2] = 6.5
+	if got, want := a.Diff(&b), "[1][2]: 6 6.5 (delta 0.5)"; got != want {
+		t.Errorf("Diff: got %q, want %q", got, want)
+	}
+	b[0][0] = 2 + 1e-12
+	if got, want := a.DiffTol(&b, 1e-9), "[1][2]: 6 6.5 (delta 0.5)"; got != want {
+		t.Errorf("DiffTol: got %q, want %q", got, want)
+	}
+	if got := a.DiffTol(&b, 1); got != "" {
+		t.Errorf("DiffTol(1): got %q", got)
+	}
+	b[2][2] = math.NaN()
+	if got, want := a.DiffTol(&b, 1), "[2][2]: 1 NaN (delta NaN)"; got != want {
+		t.Errorf("DiffTol with NaN: got %q, want %q", got, want)
+	}
+}
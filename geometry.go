@@ -0,0 +1,306 @@
+package mtransform
+
+import (
+	"errors"
+	"iter"
+	"math"
+)
+
+var ErrCollinear = errors.New("mtransform: source points are collinear")
+
+// ErrNotParallelogram is returned by MapUnitSquareToQuad when the four corners
+// cannot be hit by one affine map.
+var ErrNotParallelogram = errors.New("mtransform: quad is not a parallelogram")
+
+type Point struct {
+	X, Y float64
+}
+
+func (t *Transform) TranslatePoint(p Point) {
+	t.Translate(p.X, p.Y)
+}
+
+// ScalePoint scales by p.X along x and p.Y along y.
+func (t *Transform) ScalePoint(p Point) {
+	t.Scale(p.X, p.Y)
+}
+
+// RotateAround is RotatePoint with the pivot given as a Point. The name
+// differs from RotateAroundPointDeg, which takes the pivot as two scalars.
+func (t *Transform) RotateAround(angle float64, center Point) {
+	t.RotatePoint(angle, center.X, center.Y)
+}
+
+func (t *Transform) RotateAroundDeg(deg float64, center Point) {
+	t.RotatePoint(radians(deg), center.X, center.Y)
+}
+
+// ScaleAround is ScaleAroundPoint with the pivot given as a Point.
+func (t *Transform) ScaleAround(sx, sy float64, center Point) {
+	t.ScaleAroundPoint(sx, sy, center.X, center.Y)
+}
+
+func (t *Transform) ApplyToPoint(p Point) Point {
+	x, y := t.Apply(p.X, p.Y)
+	return Point{x, y}
+}
+
+// ApplyXY is Apply returning a Point.
+func (t *Transform) ApplyXY(x, y float64) Point {
+	return t.ApplyToPoint(Point{x, y})
+}
+
+// ApplyToPointPtr transforms *p in place.
+func (t *Transform) ApplyToPointPtr(p *Point) {
+	p.X, p.Y = t.Apply(p.X, p.Y)
+}
+
+// ApplyDeltaPoint returns the transformed displacement from from to to, equal
+// to ApplyToPoint(to) minus ApplyToPoint(from).
+func (t *Transform) ApplyDeltaPoint(from, to Point) Point {
+	x, y := t.ApplyDelta(to.X-from.X, to.Y-from.Y)
+	return Point{x, y}
+}
+
+func (t *Transform) ApplyToPoints(points []Point) []Point {
+	r := make([]Point, len(points))
+	for i, p := range points {
+		r[i] = t.ApplyToPoint(p)
+	}
+	return r
+}
+
+func (t *Transform) ApplyToPointsInPlace(points []Point) {
+	for i, p := range points {
+		points[i] = t.ApplyToPoint(p)
+	}
+}
+
+// InverseApplyToPoints maps points back through the transform, inverting it
+// once for the whole slice. It returns ErrNotInvertible before touching any
+// point if there is no inverse.
+func (t *Transform) InverseApplyToPoints(points []Point) ([]Point, error) {
+	inv, err := t.Invert()
+	if err != nil {
+		return nil, err
+	}
+	return inv.ApplyToPoints(points), nil
+}
+
+func (t *Transform) InverseApplyToPointsInPlace(points []Point) error {
+	inv, err := t.Invert()
+	if err != nil {
+		return err
+	}
+	inv.ApplyToPointsInPlace(points)
+	return nil
+}
+
+// TransformSeq lazily transforms each point as it is pulled from points.
+func (t *Transform) TransformSeq(points iter.Seq[Point]) iter.Seq[Point] {
+	return func(yield func(Point) bool) {
+		for p := range points {
+			if !yield(t.ApplyToPoint(p)) {
+				return
+			}
+		}
+	}
+}
+
+// Basis returns the images of the origin and of the points (1, 0) and (0, 1),
+// i.e. the local coordinate frame as drawn in output space.
+func (t *Transform) Basis() (origin, xAxis, yAxis Point) {
+	return t.ApplyXY(0, 0), t.ApplyXY(1, 0), t.ApplyXY(0, 1)
+}
+
+// TransformedBounds returns the axis-aligned box enclosing the transformed
+// points without building a transformed slice. Empty input gives two zero
+// points.
+func (t *Transform) TransformedBounds(points []Point) (min, max Point) {
+	if len(points) == 0 {
+		return
+	}
+	min = t.ApplyToPoint(points[0])
+	max = min
+	for _, p := range points[1:] {
+		q := t.ApplyToPoint(p)
+		min.X, min.Y = math.Min(min.X, q.X), math.Min(min.Y, q.Y)
+		max.X, max.Y = math.Max(max.X, q.X), math.Max(max.Y, q.Y)
+	}
+	return
+}
+
+// Rect is an axis-aligned rectangle with Min at or below Max on both axes.
+type Rect struct {
+	Min, Max Point
+}
+
+// ApplyToRect returns the axis-aligned box enclosing the transformed corners
+// of r.
+func (t *Transform) ApplyToRect(r Rect) Rect {
+	min, max := t.TransformedBounds([]Point{r.Min, {r.Max.X, r.Min.Y}, r.Max, {r.Min.X, r.Max.Y}})
+	return Rect{min, max}
+}
+
+type Segment struct {
+	A, B Point
+}
+
+func (t *Transform) ApplyToSegment(s Segment) Segment {
+	return Segment{t.ApplyToPoint(s.A), t.ApplyToPoint(s.B)}
+}
+
+func (t *Transform) ApplyToSegments(segments []Segment) []Segment {
+	r := make([]Segment, len(segments))
+	for i, s := range segments {
+		r[i] = t.ApplyToSegment(s)
+	}
+	return r
+}
+
+func (t *Transform) ApplyToSegmentsInPlace(segments []Segment) {
+	for i, s := range segments {
+		segments[i] = t.ApplyToSegment(s)
+	}
+}
+
+func signedArea(poly []Point) float64 {
+	var a float64
+	for i, p := range poly {
+		q := poly[(i+1)%len(poly)]
+		a += p.X*q.Y - q.X*p.Y
+	}
+	return a / 2
+}
+
+// centroid returns the area centroid of poly, or the mean of its vertices when
+// the area is zero.
+func centroid(poly []Point) Point {
+	var c Point
+	if len(poly) == 0 {
+		return c
+	}
+	a := signedArea(poly)
+	if a == 0 {
+		for _, p := range poly {
+			c.X += p.X
+			c.Y += p.Y
+		}
+		c.X /= float64(len(poly))
+		c.Y /= float64(len(poly))
+		return c
+	}
+	for i, p := range poly {
+		q := poly[(i+1)%len(poly)]
+		cross := p.X*q.Y - q.X*p.Y
+		c.X += (p.X + q.X) * cross
+		c.Y += (p.Y + q.Y) * cross
+	}
+	c.X /= 6 * a
+	c.Y /= 6 * a
+	return c
+}
+
+// TransformedPolygonArea returns the signed area of poly after transforming
+// it, which is its original signed area times the determinant.
+func (t *Transform) TransformedPolygonArea(poly []Point) float64 {
+	return signedArea(poly) * t.Determinant()
+}
+
+// TransformedCentroid returns the centroid of poly after transforming it.
+// Affine maps carry centroids to centroids, so only one point is transformed.
+func (t *Transform) TransformedCentroid(poly []Point) Point {
+	return t.ApplyToPoint(centroid(poly))
+}
+
+// AffineFromThreePoints returns the unique affine transform mapping s0, s1, s2
+// onto d0, d1, d2, or ErrCollinear when the source points do not span the
+// plane.
+func AffineFromThreePoints(s0, s1, s2, d0, d1, d2 Point) (*Transform, error) {
+	ux, uy := s1.X-s0.X, s1.Y-s0.Y
+	vx, vy := s2.X-s0.X, s2.Y-s0.Y
+	det := ux*vy - uy*vx
+	if math.Abs(det) <= 1e-12*math.Hypot(ux, uy)*math.Hypot(vx, vy) {
+		return nil, ErrCollinear
+	}
+	Ux, Uy := d1.X-d0.X, d1.Y-d0.Y
+	Vx, Vy := d2.X-d0.X, d2.Y-d0.Y
+	t := NewTransform()
+	t[0][0] = (Ux*vy - Vx*uy) / det
+	t[0][1] = (Vx*ux - Ux*vx) / det
+	t[1][0] = (Uy*vy - Vy*uy) / det
+	t[1][1] = (Vy*ux - Uy*vx) / det
+	t[0][2] = d0.X - t[0][0]*s0.X - t[0][1]*s0.Y
+	t[1][2] = d0.Y - t[1][0]*s0.X - t[1][1]*s0.Y
+	return t, nil
+}
+
+// MapUnitSquareToQuad returns the affine transform taking the unit square
+// corners (0,0), (1,0), (1,1), (0,1) to p00, p10, p11, p01. The map is fitted
+// exactly to p00, p10 and p01; p11 must then lie within defaultEpsilon of
+// p10 + p01 - p00, relative to the size of the quad, or ErrNotParallelogram is
+// returned, since no affine map can hit a general quad.
+func MapUnitSquareToQuad(p00, p10, p11, p01 Point) (*Transform, error) {
+	t := NewTransform()
+	t[0][0], t[1][0] = p10.X-p00.X, p10.Y-p00.Y
+	t[0][1], t[1][1] = p01.X-p00.X, p01.Y-p00.Y
+	t[0][2], t[1][2] = p00.X, p00.Y
+	size := math.Hypot(t[0][0], t[1][0]) + math.Hypot(t[0][1], t[1][1]) + math.Hypot(p00.X, p00.Y)
+	x, y := t.Apply(1, 1)
+	if !(math.Hypot(p11.X-x, p11.Y-y) <= defaultEpsilon*size) {
+		return nil, ErrNotParallelogram
+	}
+	return t, nil
+}
+
+// Line is the infinite line through Origin along Direction.
+type Line struct {
+	Origin, Direction Point
+}
+
+// ApplyToLine maps the origin with Apply and the direction with ApplyVector,
+// then renormalises the direction to unit length.
+func (t *Transform) ApplyToLine(l Line) Line {
+	dx, dy := t.ApplyVector(l.Direction.X, l.Direction.Y)
+	if n := math.Hypot(dx, dy); n != 0 {
+		dx, dy = dx/n, dy/n
+	}
+	return Line{t.ApplyToPoint(l.Origin), Point{dx, dy}}
+}
+
+// ClosestPoint returns the orthogonal projection of p onto the line, or the
+// origin when the direction is zero.
+func (l Line) ClosestPoint(p Point) Point {
+	dd := l.Direction.X*l.Direction.X + l.Direction.Y*l.Direction.Y
+	if dd == 0 {
+		return l.Origin
+	}
+	s := ((p.X-l.Origin.X)*l.Direction.X + (p.Y-l.Origin.Y)*l.Direction.Y) / dd
+	return Point{l.Origin.X + s*l.Direction.X, l.Origin.Y + s*l.Direction.Y}
+}
+
+// TransformCircle returns the ellipse that a circle of radius r centred on
+// (cx, cy) becomes. The axes are r times the singular values of the linear
+// block and rotation is the angle of the major axis in radians.
+func (t *Transform) TransformCircle(cx, cy, r float64) (center Point, majorAxis, minorAxis, rotation float64) {
+	s1, s2 := t.SingularValues()
+	p := t[0][0]*t[0][0] + t[0][1]*t[0][1]
+	q := t[0][0]*t[1][0] + t[0][1]*t[1][1]
+	s := t[1][0]*t[1][0] + t[1][1]*t[1][1]
+	rotation = math.Atan2(2*q, p-s) / 2
+	r = math.Abs(r)
+	return t.ApplyToPoint(Point{cx, cy}), r * s1, r * s2, rotation
+}
+
+// TransformEllipse maps the ellipse centred on (cx, cy) with radii rx, ry and
+// its rx axis at rotation radians, returning the image ellipse with nrx as the
+// major radius and nrotation as the major axis angle. The ellipse is the unit
+// circle under E = Translate·Rotate·Scale, so its image is the unit circle
+// under t·E; this is the conic transform M⁻ᵀ·C·M⁻¹ without inverting t, so a
+// singular t still yields a (flat) ellipse.
+func (t *Transform) TransformEllipse(cx, cy, rx, ry, rotation float64) (ncx, ncy, nrx, nry, nrotation float64) {
+	e := *t
+	e.MultiplyWith(*NewTRS(cx, cy, rotation, rx, ry))
+	c, major, minor, angle := e.TransformCircle(0, 0, 1)
+	return c.X, c.Y, major, minor, angle
+}
@@ -0,0 +1,348 @@
+package mtransform
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestTranslateScalePoint(t *testing.T) {
+	a, b := NewTransform(), NewTransform()
+	a.TranslatePoint(Point{3, -4})
+	a.ScalePoint(Point{2, 5})
+	b.Translate(3, -4)
+	b.Scale(2, 5)
+	if !a.Equals(b) {
+		t.Errorf("TranslatePoint/ScalePoint: got %v, want %v", *a, *b)
+	}
+}
+
+func TestPointPivots(t *testing.T) {
+	c := Point{2, -3}
+	a, b := NewTransform(), NewTransform()
+	a.RotateAround(0.4, c)
+	b.RotatePoint(0.4, 2, -3)
+	if *a != *b {
+		t.Errorf("RotateAround: got %v, want %v", *a, *b)
+	}
+	a, b = NewTransform(), NewTransform()
+	a.RotateAroundDeg(30, c)
+	b.RotatePoint(math.Pi/6, 2, -3)
+	if !nearTransform(a, b) {
+		t.Errorf("RotateAroundDeg: got %v, want %v", *a, *b)
+	}
+	a, b = NewTransform(), NewTransform()
+	a.ScaleAround(2, 0.5, c)
+	b.ScaleAroundPoint(2, 0.5, 2, -3)
+	if *a != *b {
+		t.Errorf("ScaleAround: got %v, want %v", *a, *b)
+	}
+}
+
+func TestApplyXY(t *testing.T) {
+	tr := NewTRS(1, 2, 0.5, 2, 3)
+	x, y := tr.Apply(-3, 7)
+	if got := tr.ApplyXY(-3, 7); got != (Point{x, y}) {
+		t.Errorf("ApplyXY: got %v, want %v", got, Point{x, y})
+	}
+}
+
+func TestApplyToPointPtr(t *testing.T) {
+	tr := NewTRS(1, 2, 0.5, 2, 3)
+	p := &Point{-3, 7}
+	shared := []*Point{p}
+	want := tr.ApplyToPoint(*p)
+	tr.ApplyToPointPtr(shared[0])
+	if *p != want {
+		t.Errorf("ApplyToPointPtr: got %v, want %v", *p, want)
+	}
+}
+
+func TestApplyDelta(t *testing.T) {
+	tr := NewTRS(0, 0, 0.5, 2, 3)
+	moved := *tr
+	moved.Translate(100, -40)
+	from, to := Point{1, 2}, Point{-3, 7}
+	want := tr.ApplyDeltaPoint(from, to)
+	if got := moved.ApplyDeltaPoint(from, to); got != want {
+		t.Errorf("ApplyDeltaPoint after Translate: got %v, want %v", got, want)
+	}
+	p, q := moved.ApplyToPoint(from), moved.ApplyToPoint(to)
+	if !near(want.X, q.X-p.X) || !near(want.Y, q.Y-p.Y) {
+		t.Errorf("ApplyDeltaPoint: got %v, want %v", want, Point{q.X - p.X, q.Y - p.Y})
+	}
+	if x, y := moved.ApplyDelta(-4, 5); x != want.X || y != want.Y {
+		t.Errorf("ApplyDelta: got (%v, %v), want %v", x, y, want)
+	}
+}
+
+func TestInverseApplyToPoints(t *testing.T) {
+	tr := NewTRS(1, 2, 0.5, 2, 3)
+	points := []Point{{0, 0}, {1, 0}, {-2, 5}}
+	got, err := tr.InverseApplyToPoints(tr.ApplyToPoints(points))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range points {
+		if !near(got[i].X, points[i].X) || !near(got[i].Y, points[i].Y) {
+			t.Errorf("InverseApplyToPoints[%d]: got %v, want %v", i, got[i], points[i])
+		}
+	}
+	moved := tr.ApplyToPoints(points)
+	if err := tr.InverseApplyToPointsInPlace(moved); err != nil {
+		t.Fatal(err)
+	}
+	for i := range points {
+		if !near(moved[i].X, points[i].X) || !near(moved[i].Y, points[i].Y) {
+			t.Errorf("InverseApplyToPointsInPlace[%d]: got %v, want %v", i, moved[i], points[i])
+		}
+	}
+	singular := NewTRS(0, 0, 0, 0, 1)
+	if _, err := singular.InverseApplyToPoints(points); err != ErrNotInvertible {
+		t.Errorf("InverseApplyToPoints singular: got %v, want %v", err, ErrNotInvertible)
+	}
+	if err := singular.InverseApplyToPointsInPlace(moved); err != ErrNotInvertible || moved[2] != got[2] {
+		t.Errorf("InverseApplyToPointsInPlace singular: got %v, %v", err, moved)
+	}
+}
+
+func BenchmarkInverseApplyToPoints(b *testing.B) {
+	tr := NewTRS(1, 2, 0.5, 2, 3)
+	points := make([]Point, 1000)
+	for i := 0; i < b.N; i++ {
+		tr.InverseApplyToPointsInPlace(points)
+	}
+}
+
+func BenchmarkInverseApplyPerPoint(b *testing.B) {
+	tr := NewTRS(1, 2, 0.5, 2, 3)
+	points := make([]Point, 1000)
+	for i := 0; i < b.N; i++ {
+		for j, p := range points {
+			points[j].X, points[j].Y, _ = tr.InverseApply(p.X, p.Y)
+		}
+	}
+}
+
+func TestTransformSeq(t *testing.T) {
+	tr := NewTRS(1, 2, 0.5, 2, 3)
+	points := []Point{{0, 0}, {1, 0}, {-2, 5}, {3, 3}}
+	want := tr.ApplyToPoints(points)
+	got := slices.Collect(tr.TransformSeq(slices.Values(points)))
+	if !slices.Equal(got, want) {
+		t.Errorf("TransformSeq: got %v, want %v", got, want)
+	}
+	for p := range tr.TransformSeq(slices.Values(points)) {
+		if p != want[0] {
+			t.Errorf("TransformSeq: first point %v, want %v", p, want[0])
+		}
+		break
+	}
+	tr.ApplyToPointsInPlace(points)
+	if !slices.Equal(points, want) {
+		t.Errorf("ApplyToPointsInPlace: got %v, want %v", points, want)
+	}
+}
+
+func TestBasis(t *testing.T) {
+	o, x, y := NewTransform().Basis()
+	if o != (Point{0, 0}) || x != (Point{1, 0}) || y != (Point{0, 1}) {
+		t.Errorf("Basis of identity: got %v, %v, %v", o, x, y)
+	}
+	o, x, y = NewTRS(3, 4, 0, 2, -1).Basis()
+	if o != (Point{3, 4}) || x != (Point{5, 4}) || y != (Point{3, 3}) {
+		t.Errorf("Basis: got %v, %v, %v, want {3 4}, {5 4}, {3 3}", o, x, y)
+	}
+}
+
+func TestTransformedBounds(t *testing.T) {
+	tr := NewTransform()
+	tr.Translate(10, 20)
+	tr.RotateOrigin(math.Pi / 2)
+	points := []Point{{1, 0}, {3, 1}, {2, -2}, {0, 0}}
+	lo, hi := tr.TransformedBounds(points)
+	// A quarter turn maps (x, y) to (-y, x) before the translation.
+	if !near(lo.X, 9) || !near(lo.Y, 20) || !near(hi.X, 12) || !near(hi.Y, 23) {
+		t.Errorf("TransformedBounds: got %v, %v, want {9 20}, {12 23}", lo, hi)
+	}
+	if lo, hi := tr.TransformedBounds(nil); lo != (Point{}) || hi != (Point{}) {
+		t.Errorf("TransformedBounds(nil): got %v, %v", lo, hi)
+	}
+}
+
+func TestApplyToRect(t *testing.T) {
+	tr := NewTransform()
+	tr.RotatePoint(math.Pi/2, 1, 1)
+	got := tr.ApplyToRect(Rect{Point{1, 1}, Point{4, 2}})
+	if !near(got.Min.X, 0) || !near(got.Min.Y, 1) || !near(got.Max.X, 1) || !near(got.Max.Y, 4) {
+		t.Errorf("ApplyToRect: got %v, want {{0 1} {1 4}}", got)
+	}
+}
+
+func TestApplyToSegments(t *testing.T) {
+	tr := NewTransform()
+	tr.Translate(1, 2)
+	tr.Scale(2, 3)
+	in := []Segment{{Point{0, 0}, Point{1, 1}}, {Point{-1, 2}, Point{3, -4}}}
+	want := []Segment{{Point{1, 2}, Point{3, 5}}, {Point{-1, 8}, Point{7, -10}}}
+	got := tr.ApplyToSegments(in)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ApplyToSegments[%d]: got %v, want %v", i, got[i], want[i])
+		}
+	}
+	if in[0].B != (Point{1, 1}) {
+		t.Errorf("ApplyToSegments modified its input")
+	}
+	tr.ApplyToSegmentsInPlace(in)
+	for i := range want {
+		if in[i] != want[i] {
+			t.Errorf("ApplyToSegmentsInPlace[%d]: got %v, want %v", i, in[i], want[i])
+		}
+	}
+}
+
+func TestTransformedPolygon(t *testing.T) {
+	poly := []Point{{0, 0}, {4, 0}, {4, 3}, {1, 5}}
+	tr := NewTRS(2, -1, 0.4, 1.5, -0.5)
+	tr.SkewX(0.2)
+	moved := make([]Point, len(poly))
+	for i, p := range poly {
+		moved[i] = tr.ApplyToPoint(p)
+	}
+	if got, want := tr.TransformedPolygonArea(poly), signedArea(moved); !near(got, want) {
+		t.Errorf("TransformedPolygonArea: got %v, want %v", got, want)
+	}
+	got, want := tr.TransformedCentroid(poly), centroid(moved)
+	if !near(got.X, want.X) || !near(got.Y, want.Y) {
+		t.Errorf("TransformedCentroid: got %v, want %v", got, want)
+	}
+	if c := centroid([]Point{{0, 0}, {2, 0}, {2, 2}, {0, 2}}); c != (Point{1, 1}) {
+		t.Errorf("centroid of square: got %v, want {1 1}", c)
+	}
+}
+
+func TestAffineFromThreePoints(t *testing.T) {
+	want := NewTRS(5, -3, 0.7, 2, -0.5)
+	want.SkewX(0.3)
+	s0, s1, s2 := Point{1, 2}, Point{-3, 4}, Point{0, -1}
+	got, err := AffineFromThreePoints(s0, s1, s2,
+		want.ApplyToPoint(s0), want.ApplyToPoint(s1), want.ApplyToPoint(s2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !nearTransform(got, want) {
+		t.Errorf("AffineFromThreePoints: got %v, want %v", *got, *want)
+	}
+	_, err = AffineFromThreePoints(Point{0, 0}, Point{1, 1}, Point{3, 3}, s0, s1, s2)
+	if err != ErrCollinear {
+		t.Errorf("AffineFromThreePoints collinear: got %v, want %v", err, ErrCollinear)
+	}
+}
+
+func TestMapUnitSquareToQuad(t *testing.T) {
+	want := NewTRS(2, 1, 0.4, 3, 2)
+	want.SkewX(0.2)
+	corners := []Point{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+	var q [4]Point
+	for i, c := range corners {
+		q[i] = want.ApplyToPoint(c)
+	}
+	got, err := MapUnitSquareToQuad(q[0], q[1], q[2], q[3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !nearTransform(got, want) {
+		t.Errorf("MapUnitSquareToQuad parallelogram: got %v, want %v", *got, *want)
+	}
+	for i, c := range corners {
+		if p := got.ApplyToPoint(c); !near(p.X, q[i].X) || !near(p.Y, q[i].Y) {
+			t.Errorf("MapUnitSquareToQuad: %v maps to %v, want %v", c, p, q[i])
+		}
+	}
+
+	// Noise at the level of rounding in p11 is tolerated.
+	q[2].X += 1e-14
+	if _, err := MapUnitSquareToQuad(q[0], q[1], q[2], q[3]); err != nil {
+		t.Errorf("MapUnitSquareToQuad with rounding noise: got %v", err)
+	}
+
+	// Pushing p11 out by (1,1) breaks the parallelogram.
+	q[2].X, q[2].Y = q[2].X+1, q[2].Y+1
+	if _, err := MapUnitSquareToQuad(q[0], q[1], q[2], q[3]); err != ErrNotParallelogram {
+		t.Errorf("MapUnitSquareToQuad skewed: got %v, want %v", err, ErrNotParallelogram)
+	}
+}
+
+func TestApplyToLine(t *testing.T) {
+	l := Line{Point{1, 1}, Point{1, 0}}
+	tr := NewTransform()
+	tr.Translate(3, 4)
+	got := tr.ApplyToLine(l)
+	if got != (Line{Point{4, 5}, Point{1, 0}}) {
+		t.Errorf("ApplyToLine translation: got %v", got)
+	}
+	tr.Scale(3, 3)
+	tr.RotateOrigin(math.Pi / 2)
+	got = tr.ApplyToLine(l)
+	if !near(got.Direction.X, 0) || !near(got.Direction.Y, 1) {
+		t.Errorf("ApplyToLine: direction %v is not the unit y axis", got.Direction)
+	}
+	if p := got.ClosestPoint(Point{10, 7}); !near(p.X, got.Origin.X) || !near(p.Y, 7) {
+		t.Errorf("ClosestPoint: got %v, want (%v, 7)", p, got.Origin.X)
+	}
+	if p := (Line{Point{0, 0}, Point{2, 2}}).ClosestPoint(Point{2, 0}); p != (Point{1, 1}) {
+		t.Errorf("ClosestPoint: got %v, want {1 1}", p)
+	}
+}
+
+func TestTransformCircle(t *testing.T) {
+	s := NewTransform()
+	s.Scale(2, 1)
+	c, major, minor, rot := s.TransformCircle(0, 0, 1)
+	if c != (Point{0, 0}) || !near(major, 2) || !near(minor, 1) || !near(rot, 0) {
+		t.Errorf("TransformCircle Scale(2,1): got %v, %v, %v, %v", c, major, minor, rot)
+	}
+	r := NewTRS(5, 6, math.Pi/6, 1, 3)
+	c, major, minor, rot = r.TransformCircle(1, 0, 2)
+	if want := r.ApplyToPoint(Point{1, 0}); c != want {
+		t.Errorf("TransformCircle: center %v, want %v", c, want)
+	}
+	if !near(major, 6) || !near(minor, 2) || !near(math.Cos(rot-(math.Pi/6+math.Pi/2))*math.Cos(rot-(math.Pi/6+math.Pi/2)), 1) {
+		t.Errorf("TransformCircle rotated: got %v, %v, %v", major, minor, rot)
+	}
+}
+
+func TestTransformEllipse(t *testing.T) {
+	s := NewTransform()
+	s.Scale(2, 1)
+	cx, cy, rx, ry, rot := s.TransformEllipse(1, 1, 3, 3, 0)
+	if !near(cx, 2) || !near(cy, 1) || !near(rx, 6) || !near(ry, 3) || !near(rot, 0) {
+		t.Errorf("TransformEllipse circle by Scale(2,1): got (%v, %v) %v, %v, %v", cx, cy, rx, ry, rot)
+	}
+
+	// Rotating an axis-aligned ellipse only rotates it.
+	r := NewTRS(0, 0, 0.5, 1, 1)
+	cx, cy, rx, ry, rot = r.TransformEllipse(0, 0, 4, 1, 0.25)
+	if !near(cx, 0) || !near(cy, 0) || !near(rx, 4) || !near(ry, 1) || !near(rot, 0.75) {
+		t.Errorf("TransformEllipse rotated: got (%v, %v) %v, %v, %v", cx, cy, rx, ry, rot)
+	}
+
+	// Check the boundary against the conic M⁻ᵀ·C·M⁻¹ for a general transform.
+	m := NewTRS(3, -2, 0.3, 2, 0.7)
+	m.SkewX(0.4)
+	cx, cy, rx, ry, rot = m.TransformEllipse(1, 2, 3, 1.5, -0.6)
+	inv := m.MustInvert()
+	for k := 0.0; k < 2*math.Pi; k += 0.5 {
+		// A point on the result, pulled back, must lie on the original.
+		px := cx + rx*math.Cos(k)*math.Cos(rot) - ry*math.Sin(k)*math.Sin(rot)
+		py := cy + rx*math.Cos(k)*math.Sin(rot) + ry*math.Sin(k)*math.Cos(rot)
+		x, y := inv.Apply(px, py)
+		x, y = x-1, y-2
+		u := x*math.Cos(-0.6) + y*math.Sin(-0.6)
+		v := -x*math.Sin(-0.6) + y*math.Cos(-0.6)
+		if got := u*u/9 + v*v/2.25; !near(got, 1) {
+			t.Errorf("TransformEllipse: point %v pulls back to conic value %v, want 1", Point{px, py}, got)
+		}
+	}
+}
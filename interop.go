@@ -0,0 +1,180 @@
+package mtransform
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+)
+
+// ToMat3ColumnMajor lays the matrix out column by column, as expected by a GL
+// mat3 uniform.
+func (t *Transform) ToMat3ColumnMajor() [9]float32 {
+	var m [9]float32
+	for col := 0; col < 3; col++ {
+		for row := 0; row < 3; row++ {
+			m[col*3+row] = float32(t[row][col])
+		}
+	}
+	return m
+}
+
+// ToMat4ColumnMajor embeds the transform in a 4x4 matrix with an identity z
+// axis, laid out column by column as expected by a GL mat4 uniform. The
+// translation ends up at indices 12 and 13.
+func (t *Transform) ToMat4ColumnMajor() [16]float32 {
+	var m [16]float32
+	index := [3]int{0, 1, 3}
+	for col := 0; col < 3; col++ {
+		for row := 0; row < 3; row++ {
+			m[index[col]*4+index[row]] = float32(t[row][col])
+		}
+	}
+	m[10] = 1
+	return m
+}
+
+// ToAff3 returns the top two rows in row-major order, the layout of
+// golang.org/x/image/math/f64.Aff3, so the result can be passed straight to
+// x/image/draw transformers.
+func (t *Transform) ToAff3() [6]float64 {
+	return [6]float64{
+		t[0][0], t[0][1], t[0][2],
+		t[1][0], t[1][1], t[1][2],
+	}
+}
+
+func FromAff3(a [6]float64) *Transform {
+	return &Transform{
+		{a[0], a[1], a[2]},
+		{a[3], a[4], a[5]},
+		{0, 0, 1},
+	}
+}
+
+// DrawImage warps src into dst, treating the transform as the mapping from src
+// to dst coordinates and sampling the nearest source pixel. Destination pixels
+// that map outside src are left untouched. With golang.org/x/image/draw the
+// equivalent call is
+//
+//	draw.NearestNeighbor.Transform(dst, t.ToAff3(), src, src.Bounds(), draw.Src, nil)
+func (t *Transform) DrawImage(dst draw.Image, src image.Image) error {
+	inv, err := t.Invert()
+	if err != nil {
+		return err
+	}
+	sb := src.Bounds()
+	db := dst.Bounds()
+	for y := db.Min.Y; y < db.Max.Y; y++ {
+		for x := db.Min.X; x < db.Max.X; x++ {
+			sx, sy := inv.Apply(float64(x)+0.5, float64(y)+0.5)
+			p := image.Pt(int(math.Floor(sx)), int(math.Floor(sy)))
+			if p.In(sb) {
+				dst.Set(x, y, src.At(p.X, p.Y))
+			}
+		}
+	}
+	return nil
+}
+
+// Matrix is the read-only subset of gonum's mat.Matrix, so any gonum matrix
+// can be passed to FromMatrix without this package depending on gonum. For the
+// other direction, build with the gonum tag and use MatView or Dense.
+type Matrix interface {
+	Dims() (r, c int)
+	At(i, j int) float64
+}
+
+// RowMajor returns the nine elements row by row, ready for
+// mat.NewDense(3, 3, t.RowMajor()).
+func (t *Transform) RowMajor() []float64 {
+	return []float64{
+		t[0][0], t[0][1], t[0][2],
+		t[1][0], t[1][1], t[1][2],
+		t[2][0], t[2][1], t[2][2],
+	}
+}
+
+func FromMatrix(m Matrix) (*Transform, error) {
+	if r, c := m.Dims(); r != 3 || c != 3 {
+		return nil, fmt.Errorf("mtransform: matrix is %dx%d, want 3x3", r, c)
+	}
+	var t Transform
+	for i := range t {
+		for j := range t[i] {
+			t[i][j] = m.At(i, j)
+		}
+	}
+	return &t, nil
+}
+
+// FromGDALGeoTransform converts a GDAL geotransform, ordered origin x, pixel
+// width, row rotation, origin y, column rotation, pixel height, into the
+// transform from (column, row) pixel coordinates to georeferenced coordinates.
+func FromGDALGeoTransform(gt [6]float64) *Transform {
+	return &Transform{
+		{gt[1], gt[2], gt[0]},
+		{gt[4], gt[5], gt[3]},
+		{0, 0, 1},
+	}
+}
+
+func (t *Transform) ToGDALGeoTransform() [6]float64 {
+	return [6]float64{t[0][2], t[0][0], t[0][1], t[1][2], t[1][0], t[1][1]}
+}
+
+func (t *Transform) ToSlice() [][]float64 {
+	return [][]float64{
+		{t[0][0], t[0][1], t[0][2]},
+		{t[1][0], t[1][1], t[1][2]},
+		{t[2][0], t[2][1], t[2][2]},
+	}
+}
+
+func FromSlice(m [][]float64) (*Transform, error) {
+	if len(m) != 3 {
+		return nil, fmt.Errorf("mtransform: slice has %d rows, want 3", len(m))
+	}
+	var t Transform
+	for i, row := range m {
+		if len(row) != 3 {
+			return nil, fmt.Errorf("mtransform: row %d has %d columns, want 3", i, len(row))
+		}
+		copy(t[i][:], row)
+	}
+	return &t, nil
+}
+
+// ApplyToImagePoint transforms p and rounds the result to the nearest integer,
+// with halves rounded away from zero as math.Round does.
+func (t *Transform) ApplyToImagePoint(p image.Point) image.Point {
+	x, y := t.Apply(float64(p.X), float64(p.Y))
+	return image.Pt(int(math.Round(x)), int(math.Round(y)))
+}
+
+// ApplyToImageRect returns the smallest integer rectangle containing the four
+// transformed corners of r: the minimum is floored and the maximum ceiled.
+// Coordinates within 1e-9 of an integer are snapped to it first, so rounding
+// noise from rotations does not grow the rectangle.
+func (t *Transform) ApplyToImageRect(r image.Rectangle) image.Rectangle {
+	if r.Empty() {
+		return image.Rectangle{}
+	}
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, c := range [4]image.Point{r.Min, {r.Max.X, r.Min.Y}, r.Max, {r.Min.X, r.Max.Y}} {
+		x, y := t.Apply(float64(c.X), float64(c.Y))
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+	}
+	return image.Rect(
+		int(math.Floor(snapInteger(minX))), int(math.Floor(snapInteger(minY))),
+		int(math.Ceil(snapInteger(maxX))), int(math.Ceil(snapInteger(maxY))))
+}
+
+func snapInteger(v float64) float64 {
+	if r := math.Round(v); math.Abs(v-r) < 1e-9 {
+		return r
+	}
+	return v
+}
@@ -0,0 +1,38 @@
+//go:build gonum
+
+package mtransform
+
+import "gonum.org/v1/gonum/mat"
+
+// MatView is a read-only view of a Transform as a gonum mat.Matrix. It shares
+// storage with the transform, so later changes to the transform show through.
+type MatView struct {
+	t *Transform
+}
+
+// MatView returns t as a gonum mat.Matrix, so it can be passed to gonum
+// routines directly. Build with the gonum tag to use it.
+func (t *Transform) MatView() MatView {
+	return MatView{t}
+}
+
+func (v MatView) Dims() (r, c int) {
+	return 3, 3
+}
+
+func (v MatView) At(i, j int) float64 {
+	if uint(i) >= 3 || uint(j) >= 3 {
+		panic(mat.ErrIndexOutOfRange)
+	}
+	return v.t[i][j]
+}
+
+// T returns the transpose as an implicit view, as gonum's own types do.
+func (v MatView) T() mat.Matrix {
+	return mat.Transpose{Matrix: v}
+}
+
+// Dense copies the transform into a new 3x3 gonum matrix.
+func (t *Transform) Dense() *mat.Dense {
+	return mat.NewDense(3, 3, t.RowMajor())
+}
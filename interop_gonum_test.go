@@ -0,0 +1,34 @@
+//go:build gonum
+
+package mtransform
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestMatView(t *testing.T) {
+	tr := NewTRS(1, 2, 0.3, 4, 5)
+	var m mat.Matrix = tr.MatView()
+	if !mat.Equal(m, tr.Dense()) {
+		t.Errorf("MatView and Dense disagree: %v, %v", mat.Formatted(m), mat.Formatted(tr.Dense()))
+	}
+	if got := m.T().At(0, 2); got != tr[2][0] {
+		t.Errorf("MatView.T().At(0, 2): got %v, want %v", got, tr[2][0])
+	}
+
+	inv, err := tr.Inverted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p mat.Dense
+	p.Mul(tr.MatView(), inv.MatView())
+	got, err := FromMatrix(&p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.AffineEqualsTol(NewTransform(), 1e-12) {
+		t.Errorf("gonum product with the inverse: got %v, want identity", *got)
+	}
+}
@@ -0,0 +1,146 @@
+package mtransform
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestToMatColumnMajor(t *testing.T) {
+	tr := Transform{{1, 3, 5}, {2, 4, 6}, {0, 0, 1}}
+	want3 := [9]float32{1, 2, 0, 3, 4, 0, 5, 6, 1}
+	if got := tr.ToMat3ColumnMajor(); got != want3 {
+		t.Errorf("ToMat3ColumnMajor: got %v, want %v", got, want3)
+	}
+	want4 := [16]float32{
+		1, 2, 0, 0,
+		3, 4, 0, 0,
+		0, 0, 1, 0,
+		5, 6, 0, 1,
+	}
+	if got := tr.ToMat4ColumnMajor(); got != want4 {
+		t.Errorf("ToMat4ColumnMajor: got %v, want %v", got, want4)
+	}
+}
+
+func TestAff3(t *testing.T) {
+	tr := Transform{{1, 3, 5}, {2, 4, 6}, {0, 0, 1}}
+	want := [6]float64{1, 3, 5, 2, 4, 6}
+	if got := tr.ToAff3(); got != want {
+		t.Errorf("ToAff3: got %v, want %v", got, want)
+	}
+	r := NewTransform()
+	r.RotatePoint(0.8, 3, -1)
+	r.Scale(1.5, 0.5)
+	if got := FromAff3(r.ToAff3()); !got.Equals(r) {
+		t.Errorf("Aff3 round trip: got %v, want %v", *got, *r)
+	}
+}
+
+func TestDrawImage(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 2, 1))
+	src.SetGray(0, 0, color.Gray{Y: 10})
+	src.SetGray(1, 0, color.Gray{Y: 20})
+	dst := image.NewGray(image.Rect(0, 0, 4, 4))
+	tr := NewTransform()
+	tr.Translate(0, 1)
+	tr.Scale(2, 2)
+	if err := tr.DrawImage(dst, src); err != nil {
+		t.Fatal(err)
+	}
+	want := []uint8{
+		0, 0, 0, 0,
+		10, 10, 20, 20,
+		10, 10, 20, 20,
+		0, 0, 0, 0,
+	}
+	for i, v := range want {
+		if dst.Pix[i] != v {
+			t.Fatalf("DrawImage: got %v, want %v", dst.Pix, want)
+		}
+	}
+
+	tr.Scale(0, 1)
+	if err := tr.DrawImage(dst, src); err != ErrNotInvertible {
+		t.Errorf("DrawImage with singular transform: got %v, want %v", err, ErrNotInvertible)
+	}
+}
+
+type denseMatrix struct {
+	r, c int
+	data []float64
+}
+
+func (m denseMatrix) Dims() (int, int)    { return m.r, m.c }
+func (m denseMatrix) At(i, j int) float64 { return m.data[i*m.c+j] }
+
+func TestFromMatrix(t *testing.T) {
+	tr := NewTransform()
+	tr.RotatePoint(0.3, 1, 2)
+	got, err := FromMatrix(denseMatrix{3, 3, tr.RowMajor()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equals(tr) {
+		t.Errorf("FromMatrix: got %v, want %v", *got, *tr)
+	}
+	if _, err := FromMatrix(denseMatrix{2, 3, make([]float64, 6)}); err == nil {
+		t.Errorf("FromMatrix: expected an error for a 2x3 matrix")
+	}
+}
+
+func TestGDALGeoTransform(t *testing.T) {
+	gt := [6]float64{440720, 60, 0, 3751320, 0, -60}
+	tr := FromGDALGeoTransform(gt)
+	if x, y := tr.Apply(0, 0); x != 440720 || y != 3751320 {
+		t.Errorf("FromGDALGeoTransform: pixel (0,0) maps to (%v, %v), want the origin", x, y)
+	}
+	if x, y := tr.Apply(2, 3); x != 440720+120 || y != 3751320-180 {
+		t.Errorf("FromGDALGeoTransform: pixel (2,3) maps to (%v, %v)", x, y)
+	}
+	gt = [6]float64{1, 2, 3, 4, 5, 6}
+	if got := FromGDALGeoTransform(gt).ToGDALGeoTransform(); got != gt {
+		t.Errorf("GDAL round trip: got %v, want %v", got, gt)
+	}
+}
+
+func TestSlice(t *testing.T) {
+	tr := NewTRS(1, 2, 0.3, 4, 5)
+	s := tr.ToSlice()
+	s[0][0] = 0
+	if tr[0][0] == 0 {
+		t.Errorf("ToSlice shares storage with the transform")
+	}
+	got, err := FromSlice(tr.ToSlice())
+	if err != nil || !got.Equals(tr) {
+		t.Errorf("slice round trip: got %v, %v, want %v", got, err, *tr)
+	}
+	for _, m := range [][][]float64{
+		{{1, 0, 0}, {0, 1, 0}},
+		{{1, 0, 0}, {0, 1}, {0, 0, 1}},
+	} {
+		if _, err := FromSlice(m); err == nil {
+			t.Errorf("FromSlice(%v): expected an error", m)
+		}
+	}
+}
+
+func TestApplyToImage(t *testing.T) {
+	tr := NewTransform()
+	tr.Translate(0.5, -0.5)
+	tr.Scale(1.5, 1.5)
+	if got := tr.ApplyToImagePoint(image.Pt(1, 1)); got != image.Pt(2, 1) {
+		t.Errorf("ApplyToImagePoint: got %v, want (2,1)", got)
+	}
+	if got := tr.ApplyToImagePoint(image.Pt(-1, -1)); got != image.Pt(-1, -2) {
+		t.Errorf("ApplyToImagePoint: got %v, want (-1,-2)", got)
+	}
+	r := NewTransform()
+	r.RotateOriginDeg(90)
+	if got, want := r.ApplyToImageRect(image.Rect(0, 0, 4, 2)), image.Rect(-2, 0, 0, 4); got != want {
+		t.Errorf("ApplyToImageRect: got %v, want %v", got, want)
+	}
+	if got, want := tr.ApplyToImageRect(image.Rect(0, 0, 3, 3)), image.Rect(0, -1, 5, 4); got != want {
+		t.Errorf("ApplyToImageRect: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+package mtransform
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrNoLogarithm = errors.New("mtransform: transform has no real logarithm")
+
+// Exp returns the matrix exponential of t, computed by scaling and squaring.
+// Applied to the output of Log it gives back the original affine transform.
+// If any element is NaN or infinite the result has every element NaN.
+func (t *Transform) Exp() Transform {
+	if !t.IsFinite() {
+		nan := math.NaN()
+		return Transform{{nan, nan, nan}, {nan, nan, nan}, {nan, nan, nan}}
+	}
+	// Scale by 2^-s so every element is at most 1/8 and the norm below 1/2.
+	// Taking s from the exponent of the largest element keeps it bounded and
+	// avoids squaring huge elements into an infinite norm.
+	var m float64
+	for i := range t {
+		for _, v := range t[i] {
+			m = math.Max(m, math.Abs(v))
+		}
+	}
+	_, e := math.Frexp(m)
+	s := max(e+3, 0)
+	var a Transform
+	scale := math.Ldexp(1, -s)
+	for i := range t {
+		for j := range t[i] {
+			a[i][j] = t[i][j] * scale
+		}
+	}
+	r := Identity()
+	term := Identity()
+	for k := 1; k <= 20; k++ {
+		term = MultiplyTransforms(term, a)
+		for i := range term {
+			for j := range term[i] {
+				term[i][j] /= float64(k)
+				r[i][j] += term[i][j]
+			}
+		}
+	}
+	for ; s > 0; s-- {
+		r.MultiplyWith(r)
+	}
+	return r
+}
+
+// Log returns the principal logarithm of the affine transform: a matrix with a
+// zero bottom row whose Exp is t. Interpolating logarithms with Lerp and
+// exponentiating the result blends along the shortest path in the affine
+// group. It returns ErrNoLogarithm when the linear block has a non-positive
+// real eigenvalue, which includes every reflection.
+func (t *Transform) Log() (Transform, error) {
+	m := t.Trace() / 2
+	n00, n01 := t[0][0]-m, t[0][1]
+	n10, n11 := t[1][0], t[1][1]-m
+	q := n00*n00 + n01*n10
+	var s, k float64
+	switch {
+	case q < 0:
+		w := math.Sqrt(-q)
+		s = math.Log(math.Hypot(m, w))
+		k = math.Atan2(w, m) / w
+	case q > 0:
+		r := math.Sqrt(q)
+		if m-r <= 0 {
+			return Transform{}, ErrNoLogarithm
+		}
+		s = (math.Log(m+r) + math.Log(m-r)) / 2
+		k = math.Log1p(2*r/(m-r)) / (2 * r)
+	default:
+		if m <= 0 {
+			return Transform{}, ErrNoLogarithm
+		}
+		s = math.Log(m)
+		k = 1 / m
+	}
+	x := Transform{
+		{s + k*n00, k * n01, 0},
+		{k * n10, s + k*n11, 0},
+	}
+
+	// exp([[X u] [0 0]]) has V(X)u in its last column, so exponentiating with
+	// unit vectors for u gives the columns of V(X). Solve V(X)u = translation.
+	x[0][2] = 1
+	v0 := x.Exp()
+	x[0][2], x[1][2] = 0, 1
+	v1 := x.Exp()
+	a, b, c, d := v0[0][2], v1[0][2], v0[1][2], v1[1][2]
+	det := a*d - b*c
+	x[0][2] = (d*t[0][2] - b*t[1][2]) / det
+	x[1][2] = (a*t[1][2] - c*t[0][2]) / det
+	return x, nil
+}
@@ -0,0 +1,75 @@
+package mtransform
+
+import (
+	"math"
+	"testing"
+)
+
+func TestExpLog(t *testing.T) {
+	shear := NewTRS(-2, 1, 0, 1, 1)
+	shear.SkewX(0.6)
+	for _, a := range []*Transform{
+		NewTransform(),
+		NewTRS(3, -4, 0, 1, 1),
+		NewTRS(0, 0, 1.2, 1, 1),
+		NewTRS(5, 6, -1.9, 2, 0.5),
+		NewTRS(5, 6, -2.8, 1.5, 1.5),
+		NewTRS(0, 0, 0, 3, 3),
+		NewTRS(1, 1, 0, 2, 1),
+		shear,
+	} {
+		l, err := a.Log()
+		if err != nil {
+			t.Errorf("Log of %v: %v", *a, err)
+			continue
+		}
+		if l[2] != [3]float64{0, 0, 0} {
+			t.Errorf("Log of %v: bottom row %v, want zero", *a, l[2])
+		}
+		if got := l.Exp(); !got.IsNearlyEqual(a, 1e-9) {
+			t.Errorf("Exp(Log(%v)) = %v", *a, got)
+		}
+	}
+
+	r := NewTRS(0, 0, 1, 1, 1)
+	l, _ := r.Log()
+	if !near(l[1][0], 1) || !near(l[0][1], -1) || !near(l[0][0], 0) {
+		t.Errorf("Log of rotation by 1: got %v", l)
+	}
+
+	a, b := NewTRS(0, 0, 0, 1, 1), NewTRS(10, 0, math.Pi/2, 1, 1)
+	la, _ := a.Log()
+	lb, _ := b.Log()
+	mid := la.Lerp(&lb, 0.5)
+	got := mid.Exp()
+	if !near(got.GetRotation(), math.Pi/4) {
+		t.Errorf("log-space midpoint: rotation %v, want π/4", got.GetRotation())
+	}
+
+	f := NewTransform()
+	f.ReflectX()
+	if _, err := f.Log(); err != ErrNoLogarithm {
+		t.Errorf("Log of reflection: got %v, want %v", err, ErrNoLogarithm)
+	}
+	if _, err := NewTRS(0, 0, 0, -1, -2).Log(); err != ErrNoLogarithm {
+		t.Errorf("Log of negative scale: got %v, want %v", err, ErrNoLogarithm)
+	}
+}
+
+func TestExpNonFinite(t *testing.T) {
+	for _, v := range []float64{math.Inf(1), math.Inf(-1), math.NaN()} {
+		tr := Transform{{0, v, 1}, {0, 0, 2}, {0, 0, 0}}
+		got := tr.Exp()
+		for i := range got {
+			for j := range got[i] {
+				if !math.IsNaN(got[i][j]) {
+					t.Errorf("Exp with a %v element: got %v, want all NaN", v, got)
+				}
+			}
+		}
+	}
+	huge := Transform{{0, 1e300, 0}, {0, 0, 0}, {0, 0, 0}}
+	if got := huge.Exp(); got[0][0] != 1 || got[0][1] != 1e300 || got[1][1] != 1 {
+		t.Errorf("Exp of a huge nilpotent matrix: got %v", got)
+	}
+}
@@ -1,7 +1,24 @@
 package mtransform
 
-import "math"
+import (
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+)
 
+var (
+	ErrNotInvertible   = errors.New("mtransform: transform is not invertible")
+	ErrPointAtInfinity = errors.New("mtransform: point maps to infinity")
+	ErrNotAffine       = errors.New("mtransform: bottom row is projective")
+)
+
+// Transform is a 3x3 matrix acting on homogeneous 2D points. Apply and the
+// analysis methods (Determinant, IsInvertible, Invert, Decompose, GetScale and
+// the like) read only the top two rows and assume the bottom row is [0 0 1].
+// MultiplyTransforms, ApplyHomogeneous and ApplyPerspective use all nine
+// elements. Call NormalizeAffine on hand-built matrices before relying on the
+// affine methods.
 type Transform [3][3]float64
 
 func (t *Transform) Apply(x float64, y float64) (float64, float64) {
@@ -11,6 +28,96 @@ func (t *Transform) Apply(x float64, y float64) (float64, float64) {
 	return X, Y
 }
 
+// ApplyPtr is Apply writing the result through outX and outY, for filling
+// output structs at interop boundaries.
+func (t *Transform) ApplyPtr(x, y float64, outX, outY *float64) {
+	*outX, *outY = t.Apply(x, y)
+}
+
+// Inverted returns the inverse as a new value and leaves t unchanged. It
+// returns the same errors as Invert.
+func (t *Transform) Inverted() (Transform, error) {
+	inv, err := t.Invert()
+	if err != nil {
+		return Transform{}, err
+	}
+	return *inv, nil
+}
+
+// MustInvert is like Invert but panics if the transform is not invertible.
+func (t *Transform) MustInvert() Transform {
+	inv, err := t.Invert()
+	if err != nil {
+		panic(err)
+	}
+	return *inv
+}
+
+// InverseApply maps (x, y) back through the transform, returning
+// ErrNotInvertible when there is no inverse.
+func (t *Transform) InverseApply(x float64, y float64) (float64, float64, error) {
+	inv, err := t.Invert()
+	if err != nil {
+		return 0, 0, err
+	}
+	X, Y := inv.Apply(x, y)
+	return X, Y, nil
+}
+
+// ApplyHomogeneous multiplies (x, y, 1) by the full matrix, including the
+// bottom row that Apply ignores.
+func (t *Transform) ApplyHomogeneous(x float64, y float64) (X, Y, W float64) {
+	X = t[0][0]*x + t[0][1]*y + t[0][2]
+	Y = t[1][0]*x + t[1][1]*y + t[1][2]
+	W = t[2][0]*x + t[2][1]*y + t[2][2]
+	return X, Y, W
+}
+
+// ApplyPerspective is ApplyHomogeneous followed by the perspective divide. For
+// an affine matrix W is 1 and the result matches Apply.
+func (t *Transform) ApplyPerspective(x float64, y float64) (float64, float64, error) {
+	X, Y, W := t.ApplyHomogeneous(x, y)
+	if math.Abs(W) < 1e-12 {
+		return 0, 0, ErrPointAtInfinity
+	}
+	return X / W, Y / W, nil
+}
+
+// ApplyVector applies only the linear block, ignoring translation.
+func (t *Transform) ApplyVector(x float64, y float64) (float64, float64) {
+	return t[0][0]*x + t[0][1]*y, t[1][0]*x + t[1][1]*y
+}
+
+// ApplyDelta transforms a displacement such as p2 - p1. It is ApplyVector
+// under another name: translation cancels between the two points, so it is
+// ignored here and must not be used to map positions.
+func (t *Transform) ApplyDelta(dx, dy float64) (float64, float64) {
+	return t.ApplyVector(dx, dy)
+}
+
+// ApplyComplex applies t to the point with x = real(z) and y = imag(z).
+func (t *Transform) ApplyComplex(z complex128) complex128 {
+	return complex(t.Apply(real(z), imag(z)))
+}
+
+// ApplyComplexVector applies only the linear part of t to z, like ApplyVector:
+// the translation is ignored, so z is treated as a direction, not a point.
+func (t *Transform) ApplyComplexVector(z complex128) complex128 {
+	return complex(t.ApplyVector(real(z), imag(z)))
+}
+
+// ApplyF32 is Apply for float32 coordinates. The multiply-add runs in float64
+// so the result is rounded to float32 only once.
+func (t *Transform) ApplyF32(x float32, y float32) (float32, float32) {
+	return ApplyGeneric(t, x, y)
+}
+
+// ApplyGeneric applies t to a point of any float type, computing in float64.
+func ApplyGeneric[T ~float32 | ~float64](t *Transform, x T, y T) (T, T) {
+	X, Y := t.Apply(float64(x), float64(y))
+	return T(X), T(Y)
+}
+
 func Identity() Transform {
 	var t Transform
 	t[0][0] = 1
@@ -24,6 +131,65 @@ func NewTransform() *Transform {
 	return &t
 }
 
+// NewFromComponents builds the transform written in SVG as
+// matrix(a, b, c, d, e, f), mapping (x, y) to (a*x + c*y + e, b*x + d*y + f).
+func NewFromComponents(a, b, c, d, e, f float64) *Transform {
+	return &Transform{
+		{a, c, e},
+		{b, d, f},
+		{0, 0, 1},
+	}
+}
+
+// A, B, C, D, E and F return the affine parameters in the SVG
+// matrix(a, b, c, d, e, f) convention.
+func (t *Transform) A() float64 { return t[0][0] }
+func (t *Transform) B() float64 { return t[1][0] }
+func (t *Transform) C() float64 { return t[0][1] }
+func (t *Transform) D() float64 { return t[1][1] }
+func (t *Transform) E() float64 { return t[0][2] }
+func (t *Transform) F() float64 { return t[1][2] }
+
+// Components returns the affine parameters in the order accepted by
+// NewFromComponents.
+func (t *Transform) Components() (a, b, c, d, e, f float64) {
+	return t[0][0], t[1][0], t[0][1], t[1][1], t[0][2], t[1][2]
+}
+
+// NewTRS returns Translate·Rotate·Scale: points are scaled first, then
+// rotated about the origin, then translated by (tx, ty).
+func NewTRS(tx, ty, rotation, sx, sy float64) *Transform {
+	t := NewTransform()
+	t.Translate(tx, ty)
+	t.RotateOrigin(rotation)
+	t.Scale(sx, sy)
+	return t
+}
+
+// NewTRSAround is NewTRS with the scale and rotation pivoting about (cx, cy).
+func NewTRSAround(tx, ty, rotation, sx, sy, cx, cy float64) *Transform {
+	t := NewTransform()
+	t.Translate(tx+cx, ty+cy)
+	t.RotateOrigin(rotation)
+	t.Scale(sx, sy)
+	t.Translate(-cx, -cy)
+	return t
+}
+
+// NewYFlip maps (x, y) to (x, height-y), converting between y-up and y-down
+// coordinates for a viewport of the given height. It is its own inverse.
+func NewYFlip(height float64) *Transform {
+	t := NewTransform()
+	t.Translate(0, height)
+	t.ReflectX()
+	return t
+}
+
+func (t *Transform) Clone() *Transform {
+	c := *t
+	return &c
+}
+
 func MultiplyTransforms(a Transform, b Transform) Transform {
 	return Transform{
 		{
@@ -44,8 +210,54 @@ func MultiplyTransforms(a Transform, b Transform) Transform {
 	}
 }
 
+// MultiplyInto stores a·b in dst. When dst is neither a nor b the product is
+// written in place; otherwise it goes through a local first, so any of dst, a
+// and b may alias, including MultiplyInto(t, t, t).
+func MultiplyInto(dst, a, b *Transform) {
+	if dst == a || dst == b {
+		r := MultiplyTransforms(*a, *b)
+		*dst = r
+		return
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			dst[i][j] = a[i][0]*b[0][j] + a[i][1]*b[1][j] + a[i][2]*b[2][j]
+		}
+	}
+}
+
+// MultiplyWith post-multiplies a by b. b is taken by value, so
+// t.MultiplyWith(*t) squares t.
 func (a *Transform) MultiplyWith(b Transform) {
-	*a = MultiplyTransforms(*a, b)
+	MultiplyInto(a, a, &b)
+}
+
+// ComposeStable returns the product ts[0]·ts[1]·…·ts[n-1], the same
+// transform a loop of MultiplyWith calls builds, but multiplied as a balanced
+// tree of pairs. Each element then passes through log n roundings instead of
+// up to n, so the worst-case error bound is much tighter for long chains such
+// as deep scene graphs. The gain is largest when translations accumulate like
+// a long sum; for rotation-heavy chains the two orders round about equally.
+// An empty slice gives the identity.
+func ComposeStable(ts []Transform) Transform {
+	switch len(ts) {
+	case 0:
+		return Identity()
+	case 1:
+		return ts[0]
+	}
+	level := slices.Clone(ts)
+	for len(level) > 1 {
+		next := level[:0]
+		for i := 0; i+1 < len(level); i += 2 {
+			next = append(next, MultiplyTransforms(level[i], level[i+1]))
+		}
+		if len(level)%2 == 1 {
+			next = append(next, level[len(level)-1])
+		}
+		level = next
+	}
+	return level[0]
 }
 
 func (t *Transform) Scale(x float64, y float64) {
@@ -77,15 +289,106 @@ func (t *Transform) RotatePoint(angle float64, x float64, y float64) {
 	t.Translate(-x, -y)
 }
 
+// RotateToDirection rotates so the +x axis points along (dx, dy). A zero
+// vector leaves t unchanged.
+func (t *Transform) RotateToDirection(dx, dy float64) {
+	t.RotateOrigin(math.Atan2(dy, dx))
+}
+
+func (t *Transform) RotateToDirectionAround(dx, dy, cx, cy float64) {
+	t.RotatePoint(math.Atan2(dy, dx), cx, cy)
+}
+
+// RotateBetweenVectors rotates by the signed angle, in (-π, π], that takes the
+// direction of (fromX, fromY) onto that of (toX, toY). Magnitudes are ignored.
+func (t *Transform) RotateBetweenVectors(fromX, fromY, toX, toY float64) {
+	t.RotateOrigin(angleBetween(fromX, fromY, toX, toY))
+}
+
+func (t *Transform) RotateBetweenVectorsAround(fromX, fromY, toX, toY, cx, cy float64) {
+	t.RotatePoint(angleBetween(fromX, fromY, toX, toY), cx, cy)
+}
+
+func angleBetween(fromX, fromY, toX, toY float64) float64 {
+	return math.Atan2(fromX*toY-fromY*toX, fromX*toX+fromY*toY)
+}
+
+func (t *Transform) ScaleAroundPoint(sx float64, sy float64, cx float64, cy float64) {
+	t.Translate(cx, cy)
+	t.Scale(sx, sy)
+	t.Translate(-cx, -cy)
+}
+
+// ScaleInPlace scales the content about its mapped origin, along the output
+// axes. Scale also leaves the mapped origin put, because it is applied to
+// points first, but it stretches along the content's own (possibly rotated or
+// sheared) axes; ScaleInPlace stretches along the x and y of the space the
+// content is drawn into.
+func (t *Transform) ScaleInPlace(sx, sy float64) {
+	o := t.ApplyToPoint(Point{})
+	s := NewTransform()
+	s.ScaleAroundPoint(sx, sy, o.X, o.Y)
+	s.MultiplyWith(*t)
+	*t = *s
+}
+
+// ScaleAroundPointSteps returns the translate, scale and translate-back
+// matrices whose product is what ScaleAroundPoint multiplies into a transform.
+func ScaleAroundPointSteps(sx, sy, cx, cy float64) []Transform {
+	steps := []Transform{Identity(), Identity(), Identity()}
+	steps[0].Translate(cx, cy)
+	steps[1].Scale(sx, sy)
+	steps[2].Translate(-cx, -cy)
+	return steps
+}
+
+// RotateAroundPointSteps is ScaleAroundPointSteps for RotatePoint.
+func RotateAroundPointSteps(angle, cx, cy float64) []Transform {
+	steps := []Transform{Identity(), Identity(), Identity()}
+	steps[0].Translate(cx, cy)
+	steps[1].RotateOrigin(angle)
+	steps[2].Translate(-cx, -cy)
+	return steps
+}
+
+// FlipHorizontalAround mirrors x about the vertical line x = cx.
+func (t *Transform) FlipHorizontalAround(cx float64) {
+	t.ScaleAroundPoint(-1, 1, cx, 0)
+}
+
+// FlipVerticalAround mirrors y about the horizontal line y = cy.
+func (t *Transform) FlipVerticalAround(cy float64) {
+	t.ScaleAroundPoint(1, -1, 0, cy)
+}
+
 func (t *Transform) SkewX(angle float64) {
+	t.SkewXSlope(math.Tan(angle))
+}
+
+func (t *Transform) SkewY(angle float64) {
+	t.SkewYSlope(math.Tan(angle))
+}
+
+// SkewXSlope shears x by slope·y, so SkewXSlope(math.Tan(a)) equals SkewX(a).
+// Giving the slope directly avoids the tan round trip near ±π/2.
+func (t *Transform) SkewXSlope(slope float64) {
 	a := Identity()
-	a[0][1] = math.Tan(angle)
+	a[0][1] = slope
 	t.MultiplyWith(a)
 }
 
-func (t *Transform) SkewY(angle float64) {
+// SkewYSlope shears y by slope·x, so SkewYSlope(math.Tan(a)) equals SkewY(a).
+func (t *Transform) SkewYSlope(slope float64) {
 	a := Identity()
-	a[1][0] = math.Tan(angle)
+	a[1][0] = slope
+	t.MultiplyWith(a)
+}
+
+// Shear maps (x, y) to (x + shx*y, shy*x + y).
+func (t *Transform) Shear(shx float64, shy float64) {
+	a := Identity()
+	a[0][1] = shx
+	a[1][0] = shy
 	t.MultiplyWith(a)
 }
 
@@ -94,3 +397,294 @@ func (t *Transform) Equals(t2 *Transform) bool {
 		t[1][0] == t2[1][0] && t[1][1] == t2[1][1] && t[1][2] == t2[1][2] &&
 		t[2][0] == t2[2][0] && t[2][1] == t2[2][1] && t[2][2] == t2[2][2]
 }
+
+// ReflectX reflects across the x axis, mapping (x, y) to (x, -y).
+func (t *Transform) ReflectX() {
+	t.Scale(1, -1)
+}
+
+// ReflectY reflects across the y axis, mapping (x, y) to (-x, y).
+func (t *Transform) ReflectY() {
+	t.Scale(-1, 1)
+}
+
+// ExactEqual compares every element with ==, like Equals: -0 equals 0, and a
+// NaN element makes the transform unequal to everything, including itself.
+func (t *Transform) ExactEqual(t2 *Transform) bool {
+	return *t == *t2
+}
+
+// CanonicalEqual is ExactEqual except that NaN elements compare equal to each
+// other, so it can be used for caching and deduplication.
+func (t *Transform) CanonicalEqual(t2 *Transform) bool {
+	return t.canonicalBits() == t2.canonicalBits()
+}
+
+// HashKey returns a string that is equal for two transforms exactly when
+// CanonicalEqual reports them equal, for use as a map key.
+func (t *Transform) HashKey() string {
+	bits := t.canonicalBits()
+	b := make([]byte, 0, 9*16)
+	for i := range bits {
+		for j := range bits[i] {
+			b = strconv.AppendUint(b, bits[i][j], 16)
+			b = append(b, ':')
+		}
+	}
+	return string(b)
+}
+
+func (t *Transform) canonicalBits() [3][3]uint64 {
+	var bits [3][3]uint64
+	for i := range t {
+		for j, v := range t[i] {
+			switch {
+			case v == 0:
+				v = 0
+			case math.IsNaN(v):
+				v = math.NaN()
+			}
+			bits[i][j] = math.Float64bits(v)
+		}
+	}
+	return bits
+}
+
+// NormalizeAffine divides the matrix by its bottom-right element so the bottom
+// row becomes exactly [0 0 1]. It returns ErrNotAffine, leaving t unchanged,
+// when the bottom row has a projective part or a zero bottom-right element.
+func (t *Transform) NormalizeAffine() error {
+	if t[2][0] != 0 || t[2][1] != 0 || t[2][2] == 0 {
+		return ErrNotAffine
+	}
+	w := t[2][2]
+	for i := 0; i < 2; i++ {
+		for j := range t[i] {
+			t[i][j] /= w
+		}
+	}
+	t[2][2] = 1
+	return nil
+}
+
+// IsAffine reports whether the bottom row is [0 0 1] within defaultEpsilon.
+func (t *Transform) IsAffine() bool {
+	return math.Abs(t[2][0]) <= defaultEpsilon && math.Abs(t[2][1]) <= defaultEpsilon &&
+		math.Abs(t[2][2]-1) <= defaultEpsilon
+}
+
+// ForceAffine overwrites the bottom row with [0 0 1], discarding any
+// projective part, so the affine-only methods can be used safely.
+func (t *Transform) ForceAffine() {
+	t[2] = [3]float64{0, 0, 1}
+}
+
+// IsInvertible reports whether the determinant is non-negligible relative to
+// the size of the linear block, |det| > 1e-12·(a²+b²+c²+d²). A fixed absolute
+// threshold would reject well-conditioned transforms at small scales, such as
+// Scale(1e-6, 1e-6), and accept near-singular ones at large scales; the
+// relative test depends only on the shape of the transform, not its units.
+func (t *Transform) IsInvertible() bool {
+	n := t[0][0]*t[0][0] + t[0][1]*t[0][1] + t[1][0]*t[1][0] + t[1][1]*t[1][1]
+	return math.Abs(t.Determinant()) > 1e-12*n
+}
+
+// IsInvertibleTol reports whether the absolute value of the determinant
+// exceeds epsilon, for callers who know the scale of their coordinates.
+func (t *Transform) IsInvertibleTol(epsilon float64) bool {
+	return math.Abs(t.Determinant()) > epsilon
+}
+
+// Invert returns the inverse of the affine transform, or ErrNotInvertible when
+// IsInvertible reports false.
+func (t *Transform) Invert() (*Transform, error) {
+	if !t.IsInvertible() {
+		return nil, ErrNotInvertible
+	}
+	return t.invert(), nil
+}
+
+// InvertTol is Invert with the absolute determinant threshold of
+// IsInvertibleTol.
+func (t *Transform) InvertTol(epsilon float64) (*Transform, error) {
+	if !t.IsInvertibleTol(epsilon) {
+		return nil, ErrNotInvertible
+	}
+	return t.invert(), nil
+}
+
+func (t *Transform) invert() *Transform {
+	det := t.Determinant()
+	a := t[1][1] / det
+	b := -t[0][1] / det
+	c := -t[1][0] / det
+	d := t[0][0] / det
+	return &Transform{
+		{a, b, -a*t[0][2] - b*t[1][2]},
+		{c, d, -c*t[0][2] - d*t[1][2]},
+		{0, 0, 1},
+	}
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+func (t *Transform) RotateOriginDeg(deg float64) {
+	t.RotateOrigin(radians(deg))
+}
+
+func (t *Transform) RotateAroundPointDeg(deg float64, cx float64, cy float64) {
+	t.RotatePoint(radians(deg), cx, cy)
+}
+
+func (t *Transform) SkewXDeg(deg float64) {
+	t.SkewX(radians(deg))
+}
+
+func (t *Transform) SkewYDeg(deg float64) {
+	t.SkewY(radians(deg))
+}
+
+// SnapTranslation rounds the translation to the nearest multiple of step,
+// leaving the linear block untouched. A step that is not positive does nothing.
+func (t *Transform) SnapTranslation(step float64) {
+	if step <= 0 {
+		return
+	}
+	t[0][2] = math.Round(t[0][2]/step) * step
+	t[1][2] = math.Round(t[1][2]/step) * step
+}
+
+func (t *Transform) SnapTranslationInt() {
+	t.SnapTranslation(1)
+}
+
+// Round returns a copy with every element rounded to the given number of
+// decimal places. Negative zero is normalised to zero. When 10^decimals, or an
+// element scaled by it, overflows there are no digits that far down and the
+// element is left as it is; when 10^decimals underflows to zero every finite
+// element rounds to zero.
+func (t *Transform) Round(decimals int) Transform {
+	r := *t
+	r.RoundInPlace(decimals)
+	return r
+}
+
+func (t *Transform) RoundInPlace(decimals int) {
+	p := math.Pow(10, float64(decimals))
+	if math.IsInf(p, 1) {
+		return
+	}
+	for i := range t {
+		for j := range t[i] {
+			v := t[i][j]
+			switch scaled := v * p; {
+			case p == 0:
+				if !math.IsInf(v, 0) {
+					v = 0
+				}
+			case !math.IsInf(scaled, 0):
+				v = math.Round(scaled) / p
+			}
+			if v == 0 {
+				v = 0
+			}
+			t[i][j] = v
+		}
+	}
+}
+
+// IsNearlyEqual reports whether every element differs by at most epsilon.
+func (t *Transform) IsNearlyEqual(other *Transform, epsilon float64) bool {
+	for i := range t {
+		for j := range t[i] {
+			if math.Abs(t[i][j]-other[i][j]) > epsilon {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// AffineEquals is Equals restricted to a, b, c, d, e and f; the bottom row is
+// ignored.
+func (t *Transform) AffineEquals(other *Transform) bool {
+	return t.AffineEqualsTol(other, 0)
+}
+
+// AffineEqualsTol reports whether the six affine elements each differ by at
+// most epsilon.
+func (t *Transform) AffineEqualsTol(other *Transform, epsilon float64) bool {
+	for i := 0; i < 2; i++ {
+		for j := range t[i] {
+			if !(math.Abs(t[i][j]-other[i][j]) <= epsilon) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IsNearlyEqualRelative reports whether every element satisfies
+// |a-b| <= relTol*max(|a|, |b|), so large translations are compared at the
+// same relative precision as the linear block.
+func (t *Transform) IsNearlyEqualRelative(other *Transform, relTol float64) bool {
+	for i := range t {
+		for j := range t[i] {
+			a, b := t[i][j], other[i][j]
+			if math.Abs(a-b) > relTol*math.Max(math.Abs(a), math.Abs(b)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Lerp interpolates element by element. Finite factors outside [0, 1]
+// extrapolate; use LerpClamped to stay between the two transforms. A NaN or
+// -Inf factor returns the receiver and +Inf returns other, rather than
+// elements of NaN and ±Inf.
+func (t *Transform) Lerp(other *Transform, factor float64) Transform {
+	var r Transform
+	t.LerpInto(&r, other, factor)
+	return r
+}
+
+// LerpInto is Lerp writing the result into dst, which may alias t or other.
+func (t *Transform) LerpInto(dst, other *Transform, factor float64) {
+	switch {
+	case math.IsNaN(factor), math.IsInf(factor, -1):
+		*dst = *t
+		return
+	case math.IsInf(factor, 1):
+		*dst = *other
+		return
+	}
+	for i := range t {
+		for j := range t[i] {
+			dst[i][j] = t[i][j] + (other[i][j]-t[i][j])*factor
+		}
+	}
+}
+
+// LerpClamped is Lerp with the factor clamped to [0, 1], so infinite factors
+// fall back to the nearer endpoint.
+func (t *Transform) LerpClamped(other *Transform, factor float64) Transform {
+	return t.Lerp(other, math.Min(math.Max(factor, 0), 1))
+}
+
+// Between returns inverse(b)·a, the transform taking coordinates in frame a to
+// coordinates in frame b when both map their frame into a common parent.
+func Between(a, b *Transform) (*Transform, error) {
+	inv, err := b.Invert()
+	if err != nil {
+		return nil, err
+	}
+	inv.MultiplyWith(*a)
+	return inv, nil
+}
@@ -1,6 +1,8 @@
 package mtransform
 
 import (
+	"math"
+	"math/rand"
 	"testing"
 )
 
@@ -13,3 +15,711 @@ func TestMultiply(t *testing.T) {
 		t.Errorf("Multiplying: got %v, want %v", got, want)
 	}
 }
+
+func TestMultiplyInto(t *testing.T) {
+	a := Transform{{1, 2, 3}, {-1, -2, -3}, {4, 5, 6}}
+	b := Transform{{0, 1, 2}, {0, -1, -2}, {2, 1, 0}}
+	want := Transform{{6, 2, -2}, {-6, -2, 2}, {12, 5, -2}}
+	var got Transform
+	MultiplyInto(&got, &a, &b)
+	if got != want {
+		t.Errorf("MultiplyInto: got %v, want %v", got, want)
+	}
+	c := a
+	MultiplyInto(&c, &c, &b)
+	if c != want {
+		t.Errorf("MultiplyInto aliasing a: got %v, want %v", c, want)
+	}
+	c = b
+	MultiplyInto(&c, &a, &c)
+	if c != want {
+		t.Errorf("MultiplyInto aliasing b: got %v, want %v", c, want)
+	}
+}
+
+func TestMultiplyAliasing(t *testing.T) {
+	a := Transform{{1, 2, 3}, {-1, -2, -3}, {4, 5, 6}}
+	want := MultiplyTransforms(a, a)
+
+	got := a
+	got.MultiplyWith(got)
+	if got != want {
+		t.Errorf("MultiplyWith self: got %v, want %v", got, want)
+	}
+	p := &Transform{}
+	*p = a
+	p.MultiplyWith(*p)
+	if *p != want {
+		t.Errorf("MultiplyWith through pointer: got %v, want %v", *p, want)
+	}
+	got = a
+	MultiplyInto(&got, &got, &got)
+	if got != want {
+		t.Errorf("MultiplyInto(t, t, t): got %v, want %v", got, want)
+	}
+}
+
+func TestComposeStable(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for _, n := range []int{0, 1, 2, 5, 4096} {
+		ts := make([]Transform, n)
+		want := Identity()
+		for i := range ts {
+			ts[i] = *NewTRS(rng.Float64(), rng.Float64(), rng.Float64()*2e-3, 1, 1)
+			want.MultiplyWith(ts[i])
+		}
+		if got := ComposeStable(ts); !nearTransform(&got, &want) {
+			t.Errorf("ComposeStable(%d): got %v, want %v", n, got, want)
+		}
+	}
+
+	// A long chain of offsets: the loop's translation is a running sum whose
+	// error grows with the length, the pairwise product's barely moves.
+	var prevNaive float64
+	for _, n := range []int{1 << 10, 1 << 14} {
+		ts := make([]Transform, n)
+		naive := Identity()
+		for i := range ts {
+			ts[i] = *NewTRS(0.1, 0.7, 0, 1, 1)
+			naive.MultiplyWith(ts[i])
+		}
+		stable := ComposeStable(ts)
+		wx, wy := float64(n)*0.1, float64(n)*0.7
+		errNaive := math.Abs(naive[0][2]-wx) + math.Abs(naive[1][2]-wy)
+		errStable := math.Abs(stable[0][2]-wx) + math.Abs(stable[1][2]-wy)
+		if errStable > 1e-12*float64(n) || errStable*100 > errNaive {
+			t.Errorf("ComposeStable(%d offsets): error %g, naive loop %g", n, errStable, errNaive)
+		}
+		if prevNaive != 0 && errNaive < 10*prevNaive {
+			t.Errorf("naive loop error did not grow: %g then %g", prevNaive, errNaive)
+		}
+		prevNaive = errNaive
+	}
+}
+
+func BenchmarkMultiplyTransforms(b *testing.B) {
+	x := *NewTRS(3, -4, 0.6, 2, 0.5)
+	y := *NewTRS(1, 1, 0.1, 1, 1)
+	for i := 0; i < b.N; i++ {
+		x = MultiplyTransforms(x, y)
+	}
+}
+
+func BenchmarkMultiplyInto(b *testing.B) {
+	x := NewTRS(3, -4, 0.6, 2, 0.5)
+	y := NewTRS(1, 1, 0.1, 1, 1)
+	var dst Transform
+	for i := 0; i < b.N; i++ {
+		MultiplyInto(&dst, x, y)
+	}
+}
+
+func TestInvert(t *testing.T) {
+	a := NewTransform()
+	a.Translate(3, -2)
+	a.RotateOrigin(0.5)
+	a.Scale(2, 4)
+	inv, err := a.Invert()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := MultiplyTransforms(*a, *inv)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if !near(got[i][j], Identity()[i][j]) {
+				t.Errorf("Invert: a·inv = %v, want identity", got)
+			}
+		}
+	}
+	s := Transform{{1, 2, 0}, {2, 4, 0}, {0, 0, 1}}
+	if _, err := s.Invert(); err != ErrNotInvertible {
+		t.Errorf("Invert singular: got %v, want %v", err, ErrNotInvertible)
+	}
+}
+
+func TestDegreeWrappers(t *testing.T) {
+	check := func(name string, got, want *Transform) {
+		if !nearTransform(got, want) {
+			t.Errorf("%s: got %v, want %v", name, *got, *want)
+		}
+	}
+	a, b := NewTransform(), NewTransform()
+	a.RotateOriginDeg(30)
+	b.RotateOrigin(math.Pi / 6)
+	check("RotateOriginDeg", a, b)
+	a, b = NewTransform(), NewTransform()
+	a.RotateAroundPointDeg(90, 2, 3)
+	b.RotatePoint(math.Pi/2, 2, 3)
+	check("RotateAroundPointDeg", a, b)
+	a, b = NewTransform(), NewTransform()
+	a.SkewXDeg(20)
+	b.SkewX(math.Pi / 9)
+	check("SkewXDeg", a, b)
+	a, b = NewTransform(), NewTransform()
+	a.SkewYDeg(-15)
+	b.SkewY(-math.Pi / 12)
+	check("SkewYDeg", a, b)
+
+	r := NewTransform()
+	r.RotateOriginDeg(135)
+	if got := r.GetRotationDeg(); !near(got, 135) {
+		t.Errorf("GetRotationDeg: got %v, want 135", got)
+	}
+}
+
+func TestRotateToDirection(t *testing.T) {
+	a, b := NewTransform(), NewTransform()
+	a.RotateToDirection(0, 1)
+	b.RotateOriginDeg(90)
+	if !nearTransform(a, b) {
+		t.Errorf("RotateToDirection(0, 1): got %v, want %v", *a, *b)
+	}
+	a, b = NewTransform(), NewTransform()
+	a.RotateToDirectionAround(-3, 3, 1, 2)
+	b.RotateAroundPointDeg(135, 1, 2)
+	if !nearTransform(a, b) {
+		t.Errorf("RotateToDirectionAround: got %v, want %v", *a, *b)
+	}
+}
+
+func TestRotateBetweenVectors(t *testing.T) {
+	a, b := NewTransform(), NewTransform()
+	a.RotateBetweenVectors(1, 0, 0, 1)
+	b.RotateOriginDeg(90)
+	if !nearTransform(a, b) {
+		t.Errorf("RotateBetweenVectors((1, 0), (0, 1)): got %v, want %v", *a, *b)
+	}
+	a = NewTransform()
+	a.RotateBetweenVectors(2, 2, 0, -5)
+	if got := a.GetRotationDeg(); !near(got, -135) {
+		t.Errorf("RotateBetweenVectors((2, 2), (0, -5)): got %v degrees, want -135", got)
+	}
+	a = NewTransform()
+	a.RotateBetweenVectorsAround(0, 1, -1, 0, 3, 4)
+	if x, y := a.Apply(3, 5); !near(x, 2) || !near(y, 4) {
+		t.Errorf("RotateBetweenVectorsAround: (3, 5) maps to (%v, %v), want (2, 4)", x, y)
+	}
+}
+
+func TestSkewSlope(t *testing.T) {
+	a, b := NewTransform(), NewTransform()
+	a.SkewXSlope(1)
+	b.SkewXDeg(45)
+	if !nearTransform(a, b) {
+		t.Errorf("SkewXSlope(1): got %v, want %v", *a, *b)
+	}
+	a, b = NewTransform(), NewTransform()
+	a.SkewYSlope(math.Tan(0.3))
+	b.SkewY(0.3)
+	if *a != *b {
+		t.Errorf("SkewYSlope(tan(0.3)): got %v, want %v", *a, *b)
+	}
+	if x, y := a.Apply(2, 0); x != 2 || y != 2*math.Tan(0.3) {
+		t.Errorf("SkewYSlope: (2, 0) maps to (%v, %v)", x, y)
+	}
+}
+
+func nearTransform(a, b *Transform) bool {
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if !near(a[i][j], b[i][j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestNewTRS(t *testing.T) {
+	want := NewTransform()
+	want.Translate(5, 6)
+	want.RotateOrigin(0.7)
+	want.Scale(2, 3)
+	if got := NewTRS(5, 6, 0.7, 2, 3); !nearTransform(got, want) {
+		t.Errorf("NewTRS: got %v, want %v", *got, *want)
+	}
+
+	want = NewTransform()
+	want.Translate(5, 6)
+	want.RotatePoint(0.7, 1, -1)
+	want.Translate(1, -1)
+	want.Scale(2, 3)
+	want.Translate(-1, 1)
+	got := NewTRSAround(5, 6, 0.7, 2, 3, 1, -1)
+	if !nearTransform(got, want) {
+		t.Errorf("NewTRSAround: got %v, want %v", *got, *want)
+	}
+	x, y := got.Apply(1, -1)
+	if !near(x, 6) || !near(y, 5) {
+		t.Errorf("NewTRSAround: pivot maps to (%v, %v), want (6, 5)", x, y)
+	}
+}
+
+func TestNewYFlip(t *testing.T) {
+	f := NewYFlip(600)
+	if x, y := f.Apply(0, 0); x != 0 || y != 600 {
+		t.Errorf("NewYFlip(600): (0, 0) maps to (%v, %v), want (0, 600)", x, y)
+	}
+	if x, y := f.Apply(0, 600); x != 0 || y != 0 {
+		t.Errorf("NewYFlip(600): (0, 600) maps to (%v, %v), want (0, 0)", x, y)
+	}
+	if x, y := f.Apply(25, 100); x != 25 || y != 500 {
+		t.Errorf("NewYFlip(600): (25, 100) maps to (%v, %v), want (25, 500)", x, y)
+	}
+	if twice := MultiplyTransforms(*f, *f); !twice.IsIdentity() {
+		t.Errorf("NewYFlip applied twice: got %v, want identity", twice)
+	}
+}
+
+func TestComponents(t *testing.T) {
+	tr := NewFromComponents(1, 2, 3, 4, 5, 6)
+	a, b, c, d, e, f := tr.Components()
+	if a != 1 || b != 2 || c != 3 || d != 4 || e != 5 || f != 6 {
+		t.Errorf("Components: got %v %v %v %v %v %v, want 1 2 3 4 5 6", a, b, c, d, e, f)
+	}
+	if tr.A() != 1 || tr.B() != 2 || tr.C() != 3 || tr.D() != 4 || tr.E() != 5 || tr.F() != 6 {
+		t.Errorf("accessors: got %v %v %v %v %v %v, want 1 2 3 4 5 6",
+			tr.A(), tr.B(), tr.C(), tr.D(), tr.E(), tr.F())
+	}
+}
+
+func TestApplyF32(t *testing.T) {
+	tr := NewTRS(3, -4, 0.6, 2, 0.5)
+	x, y := tr.ApplyF32(1.5, -2.25)
+	X, Y := tr.Apply(1.5, -2.25)
+	if x != float32(X) || y != float32(Y) {
+		t.Errorf("ApplyF32: got (%v, %v), want (%v, %v)", x, y, float32(X), float32(Y))
+	}
+}
+
+func TestApplyGeneric(t *testing.T) {
+	tr := NewTRS(3, -4, 0.6, 2, 0.5)
+	X, Y := tr.Apply(1.5, -2.25)
+	if x, y := ApplyGeneric(tr, 1.5, -2.25); x != X || y != Y {
+		t.Errorf("ApplyGeneric[float64]: got (%v, %v), want (%v, %v)", x, y, X, Y)
+	}
+	if x, y := ApplyGeneric(tr, float32(1.5), float32(-2.25)); x != float32(X) || y != float32(Y) {
+		t.Errorf("ApplyGeneric[float32]: got (%v, %v), want (%v, %v)", x, y, float32(X), float32(Y))
+	}
+	type meters float64
+	if x, y := ApplyGeneric(tr, meters(1.5), meters(-2.25)); x != meters(X) || y != meters(Y) {
+		t.Errorf("ApplyGeneric[meters]: got (%v, %v), want (%v, %v)", x, y, X, Y)
+	}
+}
+
+func BenchmarkApplyF32(b *testing.B) {
+	tr := NewTRS(3, -4, 0.6, 2, 0.5)
+	x, y := float32(1), float32(2)
+	for i := 0; i < b.N; i++ {
+		x, y = tr.ApplyF32(x, y)
+	}
+}
+
+func BenchmarkApplyF32Manual(b *testing.B) {
+	tr := NewTRS(3, -4, 0.6, 2, 0.5)
+	x, y := float32(1), float32(2)
+	for i := 0; i < b.N; i++ {
+		X, Y := tr.Apply(float64(x), float64(y))
+		x, y = float32(X), float32(Y)
+	}
+}
+
+func TestSnapTranslation(t *testing.T) {
+	tr := NewTransform()
+	tr.Translate(3.4, 7.8)
+	tr.RotateOrigin(0.3)
+	want := *tr
+	want[0][2], want[1][2] = 3, 8
+	tr.SnapTranslationInt()
+	if *tr != want {
+		t.Errorf("SnapTranslationInt: got %v, want %v", *tr, want)
+	}
+	tr[0][2], tr[1][2] = 3.4, -7.8
+	tr.SnapTranslation(0.5)
+	if tr[0][2] != 3.5 || tr[1][2] != -8 {
+		t.Errorf("SnapTranslation(0.5): got (%v, %v), want (3.5, -8)", tr[0][2], tr[1][2])
+	}
+}
+
+func TestRound(t *testing.T) {
+	tr := Transform{{0.3333333, -0.0001, 2.0005}, {1e-12, 1, -7.77777}, {0, 0, 1}}
+	want := Transform{{0.333, 0, 2.001}, {0, 1, -7.778}, {0, 0, 1}}
+	got := tr.Round(3)
+	if got != want {
+		t.Errorf("Round(3): got %v, want %v", got, want)
+	}
+	if math.Signbit(got[0][1]) {
+		t.Errorf("Round(3): got negative zero")
+	}
+	if tr[0][0] != 0.3333333 {
+		t.Errorf("Round modified the receiver")
+	}
+	tr.RoundInPlace(3)
+	if tr != want {
+		t.Errorf("RoundInPlace(3): got %v, want %v", tr, want)
+	}
+
+	big := Transform{{0.25, -1e300, 7}, {1, 1, 0}, {0, 0, 1}}
+	if got := big.Round(400); got != big {
+		t.Errorf("Round(400): got %v, want %v", got, big)
+	}
+	if got := big.Round(20); got != big {
+		t.Errorf("Round(20): got %v, want %v", got, big)
+	}
+	if got := big.Round(-400); got != Zero() {
+		t.Errorf("Round(-400): got %v, want zero", got)
+	}
+}
+
+func TestAffineEquals(t *testing.T) {
+	a := NewTRS(3, -2, 0.5, 2, 4)
+	b := *a
+	b[2][0], b[2][2] = 1e-17, 1+1e-15
+	if a.Equals(&b) {
+		t.Errorf("Equals: perturbed bottom row compared equal")
+	}
+	if !a.AffineEquals(&b) {
+		t.Errorf("AffineEquals: got false for %v and %v", *a, b)
+	}
+	b[1][2] += 1e-12
+	if a.AffineEquals(&b) {
+		t.Errorf("AffineEquals: got true after perturbing f")
+	}
+	if !a.AffineEqualsTol(&b, 1e-9) {
+		t.Errorf("AffineEqualsTol(1e-9): got false for %v and %v", *a, b)
+	}
+}
+
+func TestIsNearlyEqual(t *testing.T) {
+	a := NewTransform()
+	a.Translate(4.5e6, -3.2e6)
+	a.RotateOrigin(0.25)
+	c := *a
+	c[0][2] *= 1 + 1e-12
+	c[1][2] *= 1 - 1e-12
+
+	if a.IsNearlyEqual(&c, 1e-9) {
+		t.Errorf("IsNearlyEqual: large translations unexpectedly equal at 1e-9")
+	}
+	if !a.IsNearlyEqualRelative(&c, 1e-9) {
+		t.Errorf("IsNearlyEqualRelative: got false for %v and %v", *a, c)
+	}
+	c[0][0] += 1e-6
+	if a.IsNearlyEqualRelative(&c, 1e-9) {
+		t.Errorf("IsNearlyEqualRelative: got true after perturbing the linear block")
+	}
+	if !a.IsNearlyEqual(a, 0) || !a.IsNearlyEqualRelative(a, 0) {
+		t.Errorf("IsNearlyEqual: transform not equal to itself")
+	}
+}
+
+func TestLerp(t *testing.T) {
+	a := NewTRS(0, 0, 0, 1, 1)
+	b := NewTRS(10, -20, 0, 3, 5)
+	if got, want := a.Lerp(b, 0.5), *NewTRS(5, -10, 0, 2, 3); got != want {
+		t.Errorf("Lerp(0.5): got %v, want %v", got, want)
+	}
+	if got, want := a.Lerp(b, 2), *NewTRS(20, -40, 0, 5, 9); got != want {
+		t.Errorf("Lerp(2): got %v, want %v", got, want)
+	}
+	if got := a.LerpClamped(b, 1.05); got != *b {
+		t.Errorf("LerpClamped(1.05): got %v, want %v", got, *b)
+	}
+	if got := a.LerpClamped(b, math.Inf(-1)); got != *a {
+		t.Errorf("LerpClamped(-Inf): got %v, want %v", got, *a)
+	}
+	if got := a.Lerp(b, math.Inf(1)); got != *b {
+		t.Errorf("Lerp(+Inf): got %v, want %v", got, *b)
+	}
+	if got := a.Lerp(b, math.Inf(-1)); got != *a {
+		t.Errorf("Lerp(-Inf): got %v, want %v", got, *a)
+	}
+	for _, got := range []Transform{a.Lerp(b, math.NaN()), a.LerpClamped(b, math.NaN())} {
+		if !got.IsFinite() {
+			t.Errorf("Lerp(NaN): got %v", got)
+		}
+	}
+}
+
+func TestLerpInto(t *testing.T) {
+	a := NewTRS(1, 2, 0.3, 1, 2)
+	b := NewTRS(-4, 6, 1.1, 3, 0.5)
+	want := a.Lerp(b, 0.25)
+	var dst Transform
+	a.LerpInto(&dst, b, 0.25)
+	if dst != want {
+		t.Errorf("LerpInto: got %v, want %v", dst, want)
+	}
+	c := *a
+	c.LerpInto(&c, b, 0.25)
+	if c != want {
+		t.Errorf("LerpInto aliasing receiver: got %v, want %v", c, want)
+	}
+	c = *b
+	a.LerpInto(&c, &c, 0.25)
+	if c != want {
+		t.Errorf("LerpInto aliasing other: got %v, want %v", c, want)
+	}
+}
+
+func BenchmarkLerp(b *testing.B) {
+	x, y := NewTRS(1, 2, 0.3, 1, 2), NewTRS(-4, 6, 1.1, 3, 0.5)
+	var dst Transform
+	for i := 0; i < b.N; i++ {
+		dst = x.Lerp(y, 0.5)
+	}
+	_ = dst
+}
+
+func BenchmarkLerpInto(b *testing.B) {
+	x, y := NewTRS(1, 2, 0.3, 1, 2), NewTRS(-4, 6, 1.1, 3, 0.5)
+	var dst Transform
+	for i := 0; i < b.N; i++ {
+		x.LerpInto(&dst, y, 0.5)
+	}
+}
+
+func TestApplyPtr(t *testing.T) {
+	tr := NewTRS(3, -4, 0.6, 2, 0.5)
+	var out struct{ X, Y float64 }
+	tr.ApplyPtr(1.5, -2.25, &out.X, &out.Y)
+	if x, y := tr.Apply(1.5, -2.25); out.X != x || out.Y != y {
+		t.Errorf("ApplyPtr: got (%v, %v), want (%v, %v)", out.X, out.Y, x, y)
+	}
+}
+
+func TestApplyComplex(t *testing.T) {
+	tr := NewTRS(3, -4, 0.6, 2, 0.5)
+	x, y := tr.Apply(1.5, -2.25)
+	if got := tr.ApplyComplex(complex(1.5, -2.25)); got != complex(x, y) {
+		t.Errorf("ApplyComplex: got %v, want %v", got, complex(x, y))
+	}
+	x, y = tr.ApplyVector(1.5, -2.25)
+	if got := tr.ApplyComplexVector(complex(1.5, -2.25)); got != complex(x, y) {
+		t.Errorf("ApplyComplexVector: got %v, want %v", got, complex(x, y))
+	}
+	if x, y := NewTRS(3, -4, 0, 1, 1).ApplyVector(1, 2); x != 1 || y != 2 {
+		t.Errorf("ApplyVector: translation applied, got (%v, %v)", x, y)
+	}
+}
+
+func TestApplyPerspective(t *testing.T) {
+	a := NewTRS(3, -4, 0.6, 2, 0.5)
+	X, Y, W := a.ApplyHomogeneous(1.5, -2)
+	x, y := a.Apply(1.5, -2)
+	if X != x || Y != y || W != 1 {
+		t.Errorf("ApplyHomogeneous: got (%v, %v, %v), want (%v, %v, 1)", X, Y, W, x, y)
+	}
+	if px, py, err := a.ApplyPerspective(1.5, -2); err != nil || px != x || py != y {
+		t.Errorf("ApplyPerspective of affine: got (%v, %v, %v), want (%v, %v)", px, py, err, x, y)
+	}
+
+	p := Transform{{1, 0, 0}, {0, 1, 0}, {0.5, 0, 1}}
+	if px, py, err := p.ApplyPerspective(2, 4); err != nil || px != 1 || py != 2 {
+		t.Errorf("ApplyPerspective: got (%v, %v, %v), want (1, 2)", px, py, err)
+	}
+	if _, _, err := p.ApplyPerspective(-2, 4); err != ErrPointAtInfinity {
+		t.Errorf("ApplyPerspective with W=0: got %v, want %v", err, ErrPointAtInfinity)
+	}
+}
+
+func TestCanonicalEqual(t *testing.T) {
+	a := Transform{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	b := Transform{{1, math.Copysign(0, -1), 0}, {0, 1, 0}, {0, 0, 1}}
+	if !a.ExactEqual(&b) || !a.CanonicalEqual(&b) || a.HashKey() != b.HashKey() {
+		t.Errorf("-0 and 0 should compare equal: %q vs %q", a.HashKey(), b.HashKey())
+	}
+	n := a
+	n[0][2] = math.NaN()
+	m := a
+	m[0][2] = math.Float64frombits(math.Float64bits(math.NaN()) | 1)
+	if n.ExactEqual(&n) {
+		t.Errorf("ExactEqual: NaN transform equal to itself")
+	}
+	if !n.CanonicalEqual(&m) || n.HashKey() != m.HashKey() {
+		t.Errorf("CanonicalEqual: NaN transforms compare unequal")
+	}
+	if n.CanonicalEqual(&a) || n.HashKey() == a.HashKey() {
+		t.Errorf("CanonicalEqual: NaN transform equal to identity")
+	}
+	cache := map[string]int{a.HashKey(): 1}
+	if cache[b.HashKey()] != 1 {
+		t.Errorf("HashKey: lookup with -0 missed the cache")
+	}
+}
+
+func TestClone(t *testing.T) {
+	a := NewTRS(1, 2, 0.5, 3, 4)
+	c := a.Clone()
+	c.Translate(1, 1)
+	if c.Equals(a) || !NewTRS(1, 2, 0.5, 3, 4).Equals(a) {
+		t.Errorf("Clone shares storage with the original")
+	}
+}
+
+func TestFlipAround(t *testing.T) {
+	h := NewTransform()
+	h.FlipHorizontalAround(5)
+	if x, y := h.Apply(5+3, 7); x != 5-3 || y != 7 {
+		t.Errorf("FlipHorizontalAround(5): (8, 7) maps to (%v, %v), want (2, 7)", x, y)
+	}
+	v := NewTransform()
+	v.FlipVerticalAround(-1)
+	if x, y := v.Apply(4, 1); x != 4 || y != -3 {
+		t.Errorf("FlipVerticalAround(-1): (4, 1) maps to (%v, %v), want (4, -3)", x, y)
+	}
+	s := NewTransform()
+	s.ScaleAroundPoint(2, 3, 1, 1)
+	if x, y := s.Apply(1, 1); x != 1 || y != 1 {
+		t.Errorf("ScaleAroundPoint: pivot maps to (%v, %v)", x, y)
+	}
+	if x, y := s.Apply(2, 2); x != 3 || y != 4 {
+		t.Errorf("ScaleAroundPoint: (2, 2) maps to (%v, %v), want (3, 4)", x, y)
+	}
+}
+
+func TestScaleInPlace(t *testing.T) {
+	tr := NewTRS(4, 5, math.Pi/2, 1, 1)
+	tr.ScaleInPlace(2, 1)
+	if x, y := tr.Apply(0, 0); !near(x, 4) || !near(y, 5) {
+		t.Errorf("ScaleInPlace: origin maps to (%v, %v), want (4, 5)", x, y)
+	}
+	// The content's x axis points along screen y, so a screen-x scale leaves
+	// (1, 0) where it was.
+	if x, y := tr.Apply(1, 0); !near(x, 4) || !near(y, 6) {
+		t.Errorf("ScaleInPlace: (1, 0) maps to (%v, %v), want (4, 6)", x, y)
+	}
+	if x, y := tr.Apply(0, 1); !near(x, 2) || !near(y, 5) {
+		t.Errorf("ScaleInPlace: (0, 1) maps to (%v, %v), want (2, 5)", x, y)
+	}
+}
+
+func TestMustInvert(t *testing.T) {
+	a := NewTRS(3, -2, 0.5, 2, 4)
+	inv, _ := a.Invert()
+	if got := a.MustInvert(); got != *inv {
+		t.Errorf("MustInvert: got %v, want %v", got, *inv)
+	}
+	if got, err := a.Inverted(); err != nil || got != *inv {
+		t.Errorf("Inverted: got %v, %v, want %v", got, err, *inv)
+	}
+	s := Transform{{1, 2, 0}, {2, 4, 0}, {0, 0, 1}}
+	if _, err := s.Inverted(); err != ErrNotInvertible {
+		t.Errorf("Inverted singular: got %v, want %v", err, ErrNotInvertible)
+	}
+	defer func() {
+		if r := recover(); r != ErrNotInvertible {
+			t.Errorf("MustInvert singular: recovered %v, want %v", r, ErrNotInvertible)
+		}
+	}()
+	s.MustInvert()
+}
+
+func TestBetween(t *testing.T) {
+	a := NewTRS(3, 4, 0.5, 2, 2)
+	b := NewTRS(-1, 7, -0.3, 0.5, 1.5)
+	if got, err := Between(a, a); err != nil || !got.IsNearlyEqual(NewTransform(), 1e-12) {
+		t.Errorf("Between(a, a): got %v, %v, want identity", got, err)
+	}
+	ab, err := Between(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pa := Point{1, -2}
+	world := a.ApplyToPoint(pa)
+	pb := ab.ApplyToPoint(pa)
+	if got := b.ApplyToPoint(pb); !near(got.X, world.X) || !near(got.Y, world.Y) {
+		t.Errorf("Between: frame b point %v maps to %v, want %v", pb, got, world)
+	}
+	if _, err := Between(a, NewTRS(0, 0, 0, 0, 1)); err != ErrNotInvertible {
+		t.Errorf("Between singular b: got %v, want %v", err, ErrNotInvertible)
+	}
+}
+
+func TestInvertTolerance(t *testing.T) {
+	small := NewTRS(1e-6, 2e-6, 0.3, 1e-6, 1e-6)
+	if small.IsInvertibleTol(1e-10) {
+		t.Errorf("IsInvertibleTol(1e-10): accepted determinant %v", small.Determinant())
+	}
+	if _, err := small.InvertTol(1e-10); err != ErrNotInvertible {
+		t.Errorf("InvertTol(1e-10): got %v, want %v", err, ErrNotInvertible)
+	}
+	inv, err := small.Invert()
+	if err != nil {
+		t.Fatalf("Invert of Scale(1e-6): %v", err)
+	}
+	if got := MultiplyTransforms(*small, *inv); !got.IsNearlyEqual(NewTransform(), 1e-9) {
+		t.Errorf("Invert of Scale(1e-6): product %v", got)
+	}
+	if inv, err := small.InvertTol(1e-20); err != nil || *inv != small.MustInvert() {
+		t.Errorf("InvertTol(1e-20): got %v, %v", inv, err)
+	}
+
+	thin := NewTRS(0, 0, 0, 1e6, 1e-9)
+	if thin.IsInvertible() {
+		t.Errorf("IsInvertible: accepted condition number 1e15")
+	}
+}
+
+func TestNormalizeAffine(t *testing.T) {
+	tr := Transform{{2, 4, 6}, {-8, 10, 12}, {0, 0, 2}}
+	x, y, w := tr.ApplyHomogeneous(1, 1)
+	if err := tr.NormalizeAffine(); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Transform{{1, 2, 3}, {-4, 5, 6}, {0, 0, 1}}); tr != want {
+		t.Errorf("NormalizeAffine: got %v, want %v", tr, want)
+	}
+	if X, Y := tr.Apply(1, 1); X != x/w || Y != y/w {
+		t.Errorf("NormalizeAffine: Apply gives (%v, %v), want (%v, %v)", X, Y, x/w, y/w)
+	}
+	if got := tr.Determinant(); got != 13 {
+		t.Errorf("Determinant after NormalizeAffine: got %v, want 13", got)
+	}
+	p := Transform{{1, 0, 0}, {0, 1, 0}, {0.5, 0, 1}}
+	if err := p.NormalizeAffine(); err != ErrNotAffine {
+		t.Errorf("NormalizeAffine projective: got %v, want %v", err, ErrNotAffine)
+	}
+	z := Transform{{1, 0, 0}, {0, 1, 0}, {0, 0, 0}}
+	if err := z.NormalizeAffine(); err != ErrNotAffine || z[0][0] != 1 {
+		t.Errorf("NormalizeAffine zero w: got %v, %v", err, z)
+	}
+}
+
+func TestForceAffine(t *testing.T) {
+	p := Transform{{2, 0, 1}, {0, 3, 2}, {0.25, -0.5, 2}}
+	if p.IsAffine() {
+		t.Errorf("IsAffine: got true for projective %v", p)
+	}
+	p.ForceAffine()
+	if !p.IsAffine() || p != (Transform{{2, 0, 1}, {0, 3, 2}, {0, 0, 1}}) {
+		t.Errorf("ForceAffine: got %v", p)
+	}
+	d := *NewTRS(1, 2, 0.3, 2, 2)
+	d[2][2] += 1e-12
+	if !d.IsAffine() {
+		t.Errorf("IsAffine: got false for %v", d)
+	}
+}
+
+func TestAroundPointSteps(t *testing.T) {
+	product := func(steps []Transform) *Transform {
+		p := NewTransform()
+		for _, s := range steps {
+			p.MultiplyWith(s)
+		}
+		return p
+	}
+	want := NewTransform()
+	want.ScaleAroundPoint(2, -3, 4, 5)
+	steps := ScaleAroundPointSteps(2, -3, 4, 5)
+	if len(steps) != 3 || !product(steps).Equals(want) {
+		t.Errorf("ScaleAroundPointSteps: product %v, want %v", *product(steps), *want)
+	}
+	want = NewTransform()
+	want.RotatePoint(0.7, 4, 5)
+	steps = RotateAroundPointSteps(0.7, 4, 5)
+	if len(steps) != 3 || !product(steps).Equals(want) {
+		t.Errorf("RotateAroundPointSteps: product %v, want %v", *product(steps), *want)
+	}
+}
@@ -0,0 +1,40 @@
+package mtransform
+
+// Op is one recorded mutator call. The set of operations is closed: it is
+// one of TranslateOp, RotateOp, ScaleOp, SkewXOp and SkewYOp.
+type Op interface {
+	apply(t *Transform)
+}
+
+// TranslateOp is a call to Translate.
+type TranslateOp struct{ X, Y float64 }
+
+// RotateOp is a call to RotateOrigin, with Angle in radians.
+type RotateOp struct{ Angle float64 }
+
+// ScaleOp is a call to Scale.
+type ScaleOp struct{ X, Y float64 }
+
+// SkewXOp is a call to SkewX, with Angle in radians.
+type SkewXOp struct{ Angle float64 }
+
+// SkewYOp is a call to SkewY, with Angle in radians.
+type SkewYOp struct{ Angle float64 }
+
+func (o TranslateOp) apply(t *Transform) { t.Translate(o.X, o.Y) }
+func (o RotateOp) apply(t *Transform)    { t.RotateOrigin(o.Angle) }
+func (o ScaleOp) apply(t *Transform)     { t.Scale(o.X, o.Y) }
+func (o SkewXOp) apply(t *Transform)     { t.SkewX(o.Angle) }
+func (o SkewYOp) apply(t *Transform)     { t.SkewY(o.Angle) }
+
+// NewOrdered builds a transform by applying ops to the identity in slice order,
+// exactly as if the matching mutators were called one after another. Each op
+// post-multiplies, so, as in an SVG transform list, the last op acts on points
+// first: {TranslateOp{10, 0}, ScaleOp{2, 2}} scales and then translates.
+func NewOrdered(ops []Op) *Transform {
+	t := NewTransform()
+	for _, op := range ops {
+		op.apply(t)
+	}
+	return t
+}
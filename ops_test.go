@@ -0,0 +1,28 @@
+package mtransform
+
+import "testing"
+
+func TestNewOrdered(t *testing.T) {
+	ops := []Op{TranslateOp{10, 0}, RotateOp{0.5}, SkewYOp{0.1}, ScaleOp{2, 2}, SkewXOp{-0.2}}
+	want := NewTransform()
+	want.Translate(10, 0)
+	want.RotateOrigin(0.5)
+	want.SkewY(0.1)
+	want.Scale(2, 2)
+	want.SkewX(-0.2)
+	if got := NewOrdered(ops); *got != *want {
+		t.Errorf("NewOrdered: got %v, want %v", *got, *want)
+	}
+
+	ts := NewOrdered([]Op{TranslateOp{10, 0}, ScaleOp{2, 2}})
+	if x, y := ts.Apply(1, 0); x != 12 || y != 0 {
+		t.Errorf("NewOrdered(translate, scale): (1, 0) maps to (%v, %v), want (12, 0)", x, y)
+	}
+	st := NewOrdered([]Op{ScaleOp{2, 2}, TranslateOp{10, 0}})
+	if x, y := st.Apply(1, 0); x != 22 || y != 0 {
+		t.Errorf("NewOrdered(scale, translate): (1, 0) maps to (%v, %v), want (22, 0)", x, y)
+	}
+	if got := NewOrdered(nil); !got.IsIdentity() {
+		t.Errorf("NewOrdered(nil): got %v, want identity", *got)
+	}
+}
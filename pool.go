@@ -0,0 +1,26 @@
+package mtransform
+
+import "sync"
+
+// Pool recycles Transforms for hot paths that build and discard many of them.
+// The zero value is ready to use.
+type Pool struct {
+	p sync.Pool
+}
+
+// Get returns an identity transform, reusing a released one when available.
+func (p *Pool) Get() *Transform {
+	t, _ := p.p.Get().(*Transform)
+	if t == nil {
+		return NewTransform()
+	}
+	*t = Identity()
+	return t
+}
+
+// Put releases t back to the pool. t must not be used afterwards.
+func (p *Pool) Put(t *Transform) {
+	if t != nil {
+		p.p.Put(t)
+	}
+}
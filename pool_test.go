@@ -0,0 +1,48 @@
+package mtransform
+
+import "testing"
+
+func TestPool(t *testing.T) {
+	var p Pool
+	a := p.Get()
+	if !a.IsIdentity() {
+		t.Errorf("Get: got %v, want identity", *a)
+	}
+	a.Translate(3, 4)
+	a.RotateOrigin(1)
+	p.Put(a)
+	for i := 0; i < 10; i++ {
+		b := p.Get()
+		if *b != Identity() {
+			t.Errorf("Get after Put: got %v, want identity", *b)
+		}
+		b.Scale(2, 2)
+		p.Put(b)
+	}
+}
+
+var poolSink *Transform
+
+func BenchmarkComposeAlloc(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		t := NewTransform()
+		t.Translate(1, 2)
+		t.RotateOrigin(0.5)
+		t.Scale(2, 3)
+		poolSink = t
+	}
+}
+
+func BenchmarkComposePool(b *testing.B) {
+	var p Pool
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		t := p.Get()
+		t.Translate(1, 2)
+		t.RotateOrigin(0.5)
+		t.Scale(2, 3)
+		poolSink = t
+		p.Put(t)
+	}
+}
@@ -0,0 +1,33 @@
+package mtransform
+
+import (
+	"math"
+	"math/rand"
+)
+
+// RandomTransform returns an invertible affine transform with translation in
+// [-100, 100), rotation in [-π, π), shear in [-1, 1) and scale magnitudes in
+// [0.1, 10) of either sign. The same rng state always gives the same result.
+func RandomTransform(rng *rand.Rand) *Transform {
+	t := RandomRigidTransform(rng)
+	t.SkewX(math.Atan(2*rng.Float64() - 1))
+	t.Scale(randomScale(rng), randomScale(rng))
+	return t
+}
+
+// RandomRigidTransform returns a rotation in [-π, π) followed by a translation
+// in [-100, 100).
+func RandomRigidTransform(rng *rand.Rand) *Transform {
+	t := NewTransform()
+	t.Translate(200*rng.Float64()-100, 200*rng.Float64()-100)
+	t.RotateOrigin(2*math.Pi*rng.Float64() - math.Pi)
+	return t
+}
+
+func randomScale(rng *rand.Rand) float64 {
+	s := math.Pow(10, 2*rng.Float64()-1)
+	if rng.Intn(2) == 0 {
+		return -s
+	}
+	return s
+}
@@ -0,0 +1,33 @@
+package mtransform
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRandomTransform(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		tr := RandomTransform(rng)
+		inv, err := tr.Invert()
+		if err != nil {
+			t.Fatalf("RandomTransform: %v is not invertible", *tr)
+		}
+		back := MultiplyTransforms(*tr, *inv)
+		if !back.IsNearlyEqual(NewTransform(), 1e-9) {
+			t.Errorf("RandomTransform: %v·inverse = %v", *tr, back)
+		}
+
+		r := RandomRigidTransform(rng)
+		if !near(r.Determinant(), 1) || math.Abs(r[0][2]) > 100 || math.Abs(r[1][2]) > 100 {
+			t.Errorf("RandomRigidTransform: %v is not a bounded rigid transform", *r)
+		}
+	}
+
+	a := RandomTransform(rand.New(rand.NewSource(7)))
+	b := RandomTransform(rand.New(rand.NewSource(7)))
+	if !a.Equals(b) {
+		t.Errorf("RandomTransform: same seed gave %v and %v", *a, *b)
+	}
+}
@@ -0,0 +1,57 @@
+package mtransform
+
+import (
+	"errors"
+	"slices"
+)
+
+var ErrNothingToUndo = errors.New("mtransform: no recorded operation to undo")
+
+// RecordingTransform builds a transform with the usual mutators while keeping
+// the list of calls, so an editor can replay or undo them.
+type RecordingTransform struct {
+	t   Transform
+	ops []Op
+}
+
+func NewRecordingTransform() *RecordingTransform {
+	return &RecordingTransform{t: Identity()}
+}
+
+func (r *RecordingTransform) record(op Op) {
+	op.apply(&r.t)
+	r.ops = append(r.ops, op)
+}
+
+func (r *RecordingTransform) Translate(x, y float64)     { r.record(TranslateOp{x, y}) }
+func (r *RecordingTransform) RotateOrigin(angle float64) { r.record(RotateOp{angle}) }
+func (r *RecordingTransform) Scale(x, y float64)         { r.record(ScaleOp{x, y}) }
+func (r *RecordingTransform) SkewX(angle float64)        { r.record(SkewXOp{angle}) }
+func (r *RecordingTransform) SkewY(angle float64)        { r.record(SkewYOp{angle}) }
+
+// Get returns the current transform.
+func (r *RecordingTransform) Get() Transform {
+	return r.t
+}
+
+// Ops returns a copy of the recorded operations, oldest first.
+func (r *RecordingTransform) Ops() []Op {
+	return slices.Clone(r.ops)
+}
+
+// Replay rebuilds the transform from the identity by applying every recorded
+// operation in order.
+func (r *RecordingTransform) Replay() *Transform {
+	return NewOrdered(r.ops)
+}
+
+// Undo drops the last operation and recomputes the transform by replaying the
+// rest, rather than multiplying by an inverse that may not exist.
+func (r *RecordingTransform) Undo() error {
+	if len(r.ops) == 0 {
+		return ErrNothingToUndo
+	}
+	r.ops = r.ops[:len(r.ops)-1]
+	r.t = *r.Replay()
+	return nil
+}
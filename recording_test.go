@@ -0,0 +1,50 @@
+package mtransform
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRecordingTransform(t *testing.T) {
+	r := NewRecordingTransform()
+	want := NewTransform()
+	r.Translate(3, -4)
+	want.Translate(3, -4)
+	r.RotateOrigin(0.6)
+	want.RotateOrigin(0.6)
+	r.SkewX(0.2)
+	want.SkewX(0.2)
+	beforeScale := *want
+	r.Scale(2, 0)
+	want.Scale(2, 0)
+
+	if got := r.Get(); got != *want {
+		t.Errorf("Get: got %v, want %v", got, *want)
+	}
+	if got := r.Replay(); *got != r.Get() {
+		t.Errorf("Replay: got %v, want %v", *got, r.Get())
+	}
+	wantOps := []Op{TranslateOp{3, -4}, RotateOp{0.6}, SkewXOp{0.2}, ScaleOp{2, 0}}
+	if got := r.Ops(); !slices.Equal(got, wantOps) {
+		t.Errorf("Ops: got %v, want %v", got, wantOps)
+	}
+
+	// The singular scale has no inverse, so undo must replay.
+	if err := r.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Get(); got != beforeScale {
+		t.Errorf("Undo: got %v, want %v", got, beforeScale)
+	}
+	if got := len(r.Ops()); got != 3 {
+		t.Errorf("Undo: %d operations left, want 3", got)
+	}
+
+	empty := NewRecordingTransform()
+	if err := empty.Undo(); err != ErrNothingToUndo {
+		t.Errorf("Undo of empty: got %v, want %v", err, ErrNothingToUndo)
+	}
+	if got := empty.Get(); !got.IsIdentity() {
+		t.Errorf("Get of empty: got %v, want identity", got)
+	}
+}
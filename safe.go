@@ -0,0 +1,32 @@
+package mtransform
+
+import "sync"
+
+// SafeTransform guards a Transform so it can be updated on one goroutine and
+// read on others without torn reads.
+type SafeTransform struct {
+	mu sync.RWMutex
+	t  Transform
+}
+
+func NewSafeTransform(t Transform) *SafeTransform {
+	return &SafeTransform{t: t}
+}
+
+func (s *SafeTransform) Set(t Transform) {
+	s.mu.Lock()
+	s.t = t
+	s.mu.Unlock()
+}
+
+func (s *SafeTransform) Get() Transform {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.t
+}
+
+func (s *SafeTransform) Apply(x float64, y float64) (float64, float64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.t.Apply(x, y)
+}
@@ -0,0 +1,41 @@
+package mtransform
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSafeTransform(t *testing.T) {
+	s := NewSafeTransform(Identity())
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			v := float64(i)
+			s.Set(Transform{{v, v, v}, {v, v, v}, {v, v, v}})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			got := s.Get()
+			for _, row := range got {
+				for _, v := range row {
+					if v != got[0][0] && got != Identity() {
+						t.Errorf("Get: torn read %v", got)
+						return
+					}
+				}
+			}
+			if x, y := s.Apply(1, 0); x != y && x != 1 {
+				t.Errorf("Apply: torn read (%v, %v)", x, y)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+	if got := s.Get(); got[2][2] != 999 {
+		t.Errorf("Get after Set: got %v", got)
+	}
+}
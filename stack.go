@@ -0,0 +1,55 @@
+package mtransform
+
+import "errors"
+
+var ErrStackUnderflow = errors.New("mtransform: cannot pop the base of the stack")
+
+// Stack is a save/restore transform stack, as used for nested groups in a
+// graphics context. It starts with an identity that cannot be popped. The zero
+// value is ready to use.
+type Stack struct {
+	s []Transform
+}
+
+func NewStack() *Stack {
+	return &Stack{s: []Transform{Identity()}}
+}
+
+func (s *Stack) init() {
+	if len(s.s) == 0 {
+		s.s = append(s.s, Identity())
+	}
+}
+
+// Push duplicates the top of the stack.
+func (s *Stack) Push() {
+	s.init()
+	s.s = append(s.s, s.s[len(s.s)-1])
+}
+
+// Pop removes and returns the top of the stack.
+func (s *Stack) Pop() (Transform, error) {
+	s.init()
+	if len(s.s) == 1 {
+		return Transform{}, ErrStackUnderflow
+	}
+	t := s.s[len(s.s)-1]
+	s.s = s.s[:len(s.s)-1]
+	return t, nil
+}
+
+// Top returns the current transform. It stays valid until the next Push or Pop.
+func (s *Stack) Top() *Transform {
+	s.init()
+	return &s.s[len(s.s)-1]
+}
+
+// Multiply post-multiplies the top of the stack by t.
+func (s *Stack) Multiply(t Transform) {
+	s.Top().MultiplyWith(t)
+}
+
+func (s *Stack) Len() int {
+	s.init()
+	return len(s.s)
+}
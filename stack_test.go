@@ -0,0 +1,40 @@
+package mtransform
+
+import "testing"
+
+func TestStack(t *testing.T) {
+	s := NewStack()
+	s.Top().Translate(10, 20)
+
+	s.Push()
+	s.Top().Scale(2, 2)
+	if x, y := s.Top().Apply(1, 1); x != 12 || y != 22 {
+		t.Errorf("nested group: got (%v, %v), want (12, 22)", x, y)
+	}
+	s.Push()
+	s.Multiply(*NewTRS(1, 0, 0, 1, 1))
+	if x, y := s.Top().Apply(1, 1); x != 14 || y != 22 {
+		t.Errorf("nested group: got (%v, %v), want (14, 22)", x, y)
+	}
+	if _, err := s.Pop(); err != nil {
+		t.Fatal(err)
+	}
+	popped, err := s.Pop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if popped != *NewTRS(10, 20, 0, 2, 2) {
+		t.Errorf("Pop: got %v", popped)
+	}
+	if x, y := s.Top().Apply(1, 1); x != 11 || y != 21 {
+		t.Errorf("after restore: got (%v, %v), want (11, 21)", x, y)
+	}
+	if _, err := s.Pop(); err != ErrStackUnderflow {
+		t.Errorf("Pop of base: got %v, want %v", err, ErrStackUnderflow)
+	}
+
+	var z Stack
+	if z.Len() != 1 || *z.Top() != Identity() {
+		t.Errorf("zero Stack: got %d entries, top %v", z.Len(), *z.Top())
+	}
+}
@@ -0,0 +1,151 @@
+package mtransform
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// ToSVGMatrix formats the transform as an SVG matrix(a b c d e f) function.
+func (t *Transform) ToSVGMatrix() string {
+	a, b, c, d, e, f := t.Components()
+	return "matrix(" + formatFloat(a) + " " + formatFloat(b) + " " +
+		formatFloat(c) + " " + formatFloat(d) + " " +
+		formatFloat(e) + " " + formatFloat(f) + ")"
+}
+
+// FromSVGMatrix parses an SVG matrix(a, b, c, d, e, f) function. The numbers
+// may be separated by commas, whitespace or both.
+func FromSVGMatrix(s string) (*Transform, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "matrix") {
+		return nil, fmt.Errorf("mtransform: %q is not an SVG matrix", s)
+	}
+	s = strings.TrimSpace(s[len("matrix"):])
+	if !strings.HasPrefix(s, "(") || !strings.HasSuffix(s, ")") {
+		return nil, fmt.Errorf("mtransform: %q is not an SVG matrix", s)
+	}
+	v, err := parseNumbers(s[1 : len(s)-1])
+	if err != nil {
+		return nil, err
+	}
+	if len(v) != 6 {
+		return nil, fmt.Errorf("mtransform: SVG matrix has %d values, want 6", len(v))
+	}
+	return NewFromComponents(v[0], v[1], v[2], v[3], v[4], v[5]), nil
+}
+
+func parseNumbers(s string) ([]float64, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+	v := make([]float64, len(fields))
+	for i, f := range fields {
+		n, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, fmt.Errorf("mtransform: invalid number %q", f)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+// ParseSVGTransform parses an SVG transform list such as
+// "translate(10 20) rotate(45) scale(2)". The functions are applied in list
+// order, matching the post-multiplying mutators.
+func ParseSVGTransform(s string) (*Transform, error) {
+	t := NewTransform()
+	rest := strings.TrimSpace(s)
+	for rest != "" {
+		start := strings.IndexByte(rest, '(')
+		end := strings.IndexByte(rest, ')')
+		if start < 0 || end < start {
+			return nil, fmt.Errorf("mtransform: malformed SVG transform %q", s)
+		}
+		name := strings.TrimSpace(rest[:start])
+		v, err := parseNumbers(rest[start+1 : end])
+		if err != nil {
+			return nil, err
+		}
+		if err := t.applySVGFunction(name, v); err != nil {
+			return nil, err
+		}
+		rest = strings.TrimLeft(rest[end+1:], " \t\n\r,")
+	}
+	return t, nil
+}
+
+func (t *Transform) applySVGFunction(name string, v []float64) error {
+	switch {
+	case name == "matrix" && len(v) == 6:
+		t.MultiplyWith(*NewFromComponents(v[0], v[1], v[2], v[3], v[4], v[5]))
+	case name == "translate" && len(v) == 1:
+		t.Translate(v[0], 0)
+	case name == "translate" && len(v) == 2:
+		t.Translate(v[0], v[1])
+	case name == "scale" && len(v) == 1:
+		t.Scale(v[0], v[0])
+	case name == "scale" && len(v) == 2:
+		t.Scale(v[0], v[1])
+	case name == "rotate" && len(v) == 1:
+		t.RotateOriginDeg(v[0])
+	case name == "rotate" && len(v) == 3:
+		t.RotateAroundPointDeg(v[0], v[1], v[2])
+	case name == "skewX" && len(v) == 1:
+		t.SkewXDeg(v[0])
+	case name == "skewY" && len(v) == 1:
+		t.SkewYDeg(v[0])
+	default:
+		return fmt.Errorf("mtransform: unsupported SVG transform %s with %d values", name, len(v))
+	}
+	return nil
+}
+
+// ToSVGTransformList writes the transform as the translate, rotate, skewX and
+// scale chain given by Decompose, leaving out operations that do nothing. The
+// identity gives an empty string. A transform that ValidateDecomposition
+// rejects, or whose chain does not read back within rounding (a near-singular
+// shear, say), is written as ToSVGMatrix instead. For a finite affine transform,
+// parsing the result gives back the matrix up to rounding. SVG cannot express
+// a projective bottom row, so one is dropped and the result parses to the
+// affine part only.
+func (t *Transform) ToSVGTransformList() string {
+	if t.ValidateDecomposition() != nil {
+		return t.ToSVGMatrix()
+	}
+	const epsilon = 1e-12
+	d := t.Decompose()
+	var ops []string
+	if d.TranslateX != 0 || d.TranslateY != 0 {
+		if d.TranslateY == 0 {
+			ops = append(ops, "translate("+formatFloat(d.TranslateX)+")")
+		} else {
+			ops = append(ops, "translate("+formatFloat(d.TranslateX)+" "+formatFloat(d.TranslateY)+")")
+		}
+	}
+	if math.Abs(d.Rotation) > epsilon {
+		ops = append(ops, "rotate("+formatFloat(degrees(d.Rotation))+")")
+	}
+	if math.Abs(d.Shear) > epsilon {
+		ops = append(ops, "skewX("+formatFloat(degrees(math.Atan(d.Shear)))+")")
+	}
+	if math.Abs(d.ScaleX-1) > epsilon || math.Abs(d.ScaleY-1) > epsilon {
+		if d.ScaleX == d.ScaleY {
+			ops = append(ops, "scale("+formatFloat(d.ScaleX)+")")
+		} else {
+			ops = append(ops, "scale("+formatFloat(d.ScaleX)+" "+formatFloat(d.ScaleY)+")")
+		}
+	}
+	list := strings.Join(ops, " ")
+	// The skewX angle is ill-conditioned near 90 degrees, so check that the
+	// text reads back as t before committing to it.
+	if back, err := ParseSVGTransform(list); err != nil || !t.affineClose(back) {
+		return t.ToSVGMatrix()
+	}
+	return list
+}
@@ -0,0 +1,110 @@
+package mtransform
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSVGMatrix(t *testing.T) {
+	got := NewFromComponents(1, 2, 3, 4, 5, 6)
+	x, y := got.Apply(10, 100)
+	if x != 1*10+3*100+5 || y != 2*10+4*100+6 {
+		t.Errorf("NewFromComponents: Apply(10, 100) = (%v, %v)", x, y)
+	}
+	if s := got.ToSVGMatrix(); s != "matrix(1 2 3 4 5 6)" {
+		t.Errorf("ToSVGMatrix: got %q", s)
+	}
+	want, err := FromSVGMatrix("matrix(1, 2,3 4 ,5, 6)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equals(want) {
+		t.Errorf("NewFromComponents: got %v, FromSVGMatrix gives %v", *got, *want)
+	}
+	for _, s := range []string{"matrix(1 2 3 4 5)", "scale(2)", "matrix(1 2 3 4 5 x)"} {
+		if _, err := FromSVGMatrix(s); err == nil {
+			t.Errorf("FromSVGMatrix(%q): expected an error", s)
+		}
+	}
+}
+
+func TestParseSVGTransform(t *testing.T) {
+	want := NewTransform()
+	want.Translate(10, 0)
+	want.RotatePoint(math.Pi/4, 1, 2)
+	want.Scale(2, 2)
+	want.SkewY(math.Pi / 6)
+	want.MultiplyWith(*NewFromComponents(1, 2, 3, 4, 5, 6))
+	got, err := ParseSVGTransform("translate(10) rotate(45, 1, 2),scale(2)\n skewY(30) matrix(1 2 3 4 5 6)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !nearTransform(got, want) {
+		t.Errorf("ParseSVGTransform: got %v, want %v", *got, *want)
+	}
+	if got, err := ParseSVGTransform(""); err != nil || !got.Equals(NewTransform()) {
+		t.Errorf("ParseSVGTransform of empty list: got %v, %v", got, err)
+	}
+	for _, s := range []string{"rotate(1 2)", "spin(3)", "translate(1 2", "scale(a)"} {
+		if _, err := ParseSVGTransform(s); err == nil {
+			t.Errorf("ParseSVGTransform(%q): expected an error", s)
+		}
+	}
+}
+
+func TestToSVGTransformList(t *testing.T) {
+	for _, c := range []struct {
+		t    *Transform
+		want string
+	}{
+		{NewTransform(), ""},
+		{NewTRS(3, 4, 0, 1, 1), "translate(3 4)"},
+		{NewTRS(3, 0, 0, 2, 2), "translate(3) scale(2)"},
+		{NewTRS(0, 0, math.Pi/2, 1, 1), "rotate(90)"},
+		{NewTRS(0, 0, 0, 2, -1), "scale(2 -1)"},
+	} {
+		if got := c.t.ToSVGTransformList(); got != c.want {
+			t.Errorf("ToSVGTransformList of %v: got %q, want %q", *c.t, got, c.want)
+		}
+	}
+
+	tr := NewTRS(-5, 7, 2.5, 3, -0.25)
+	tr.SkewX(0.4)
+	list := tr.ToSVGTransformList()
+	got, err := ParseSVGTransform(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !nearTransform(got, tr) {
+		t.Errorf("ParseSVGTransform(%q): got %v, want %v", list, *got, *tr)
+	}
+
+	parallel := NewFromComponents(1, 2, 3, 6, 0, 0)
+	if got, want := parallel.ToSVGTransformList(), "matrix(1 2 3 6 0 0)"; got != want {
+		t.Errorf("ToSVGTransformList of parallel columns: got %q, want %q", got, want)
+	}
+	if got, err := ParseSVGTransform(parallel.ToSVGTransformList()); err != nil || *got != *parallel {
+		t.Errorf("ParseSVGTransform of parallel columns: got %v, %v, want %v", got, err, *parallel)
+	}
+
+	for _, c := range []*Transform{
+		NewFromComponents(1, 2, 3, 6, 1e12, 0),
+		NewFromComponents(1, 2, 3, 6+1e-9, 1e12, -3e11),
+	} {
+		list := c.ToSVGTransformList()
+		got, err := ParseSVGTransform(list)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.AffineEqualsTol(c, 1e-9*math.Hypot(c[0][2], c[1][2])) ||
+			!got.LinearPart().AffineEqualsTol(c.LinearPart(), 1e-9) {
+			t.Errorf("ParseSVGTransform(%q): got %v, want %v", list, *got, *c)
+		}
+	}
+
+	projective := Transform{{1, 0, 2}, {0, 1, 3}, {0.5, 0, 1}}
+	got, err = ParseSVGTransform(projective.ToSVGTransformList())
+	if want := NewTRS(2, 3, 0, 1, 1); err != nil || *got != *want {
+		t.Errorf("ParseSVGTransform of projective: got %v, %v, want the affine part %v", got, err, *want)
+	}
+}
@@ -0,0 +1,62 @@
+package mtransform
+
+import "errors"
+
+// ConstrainMode selects how ConstrainToBounds keeps content on screen.
+type ConstrainMode int
+
+const (
+	// ConstrainContain keeps the content inside the viewport.
+	ConstrainContain ConstrainMode = iota
+	// ConstrainCover keeps the viewport filled by the content.
+	ConstrainCover
+)
+
+// ConstrainToBounds returns a copy of t with its translation adjusted so the
+// transformed content rectangle stays inside the viewport (ConstrainContain)
+// or covers it (ConstrainCover). Scale is left alone; on an axis where the
+// content is too large to fit, or too small to cover, it is centred instead.
+func (t *Transform) ConstrainToBounds(content, viewport Rect, mode ConstrainMode) *Transform {
+	b := t.ApplyToRect(content)
+	r := t.Clone()
+	r[0][2] += constrainAxis(b.Min.X, b.Max.X, viewport.Min.X, viewport.Max.X, mode)
+	r[1][2] += constrainAxis(b.Min.Y, b.Max.Y, viewport.Min.Y, viewport.Max.Y, mode)
+	return r
+}
+
+// constrainAxis returns the shift that brings [lo, hi] into line with
+// [vlo, vhi] on one axis.
+func constrainAxis(lo, hi, vlo, vhi float64, mode ConstrainMode) float64 {
+	fits := hi-lo <= vhi-vlo
+	if fits != (mode == ConstrainContain) {
+		return (vlo+vhi)/2 - (lo+hi)/2
+	}
+	switch {
+	case fits && lo < vlo, !fits && lo > vlo:
+		return vlo - lo
+	case fits && hi > vhi, !fits && hi < vhi:
+		return vhi - hi
+	}
+	return 0
+}
+
+// ErrInvalidZoomFactor is returned by ZoomAbout for a factor that is not
+// positive.
+var ErrInvalidZoomFactor = errors.New("mtransform: zoom factor must be positive")
+
+// ZoomAbout multiplies the zoom by factor about the output-space point
+// (cx, cy), typically the cursor: whatever is drawn at (cx, cy) stays there and
+// everything else moves away from or towards it. Rotation is unchanged. The
+// scale is applied after t, unlike ScaleAroundPoint, which scales the input.
+// A factor that is not positive returns ErrInvalidZoomFactor and leaves t
+// unchanged.
+func (t *Transform) ZoomAbout(factor, cx, cy float64) error {
+	if !(factor > 0) {
+		return ErrInvalidZoomFactor
+	}
+	z := NewTransform()
+	z.ScaleAroundPoint(factor, factor, cx, cy)
+	z.MultiplyWith(*t)
+	*t = *z
+	return nil
+}
@@ -0,0 +1,57 @@
+package mtransform
+
+import (
+	"math"
+	"testing"
+)
+
+func TestConstrainToBounds(t *testing.T) {
+	viewport := Rect{Point{0, 0}, Point{100, 100}}
+	content := Rect{Point{0, 0}, Point{50, 50}}
+	check := func(name string, tr *Transform, mode ConstrainMode, wantX, wantY float64) {
+		got := tr.ConstrainToBounds(content, viewport, mode)
+		if x, y := got.GetTranslation(); !near(x, wantX) || !near(y, wantY) {
+			t.Errorf("%s: translation (%v, %v), want (%v, %v)", name, x, y, wantX, wantY)
+		}
+		if got[0][0] != tr[0][0] || got[1][1] != tr[1][1] {
+			t.Errorf("%s: scale changed to %v", name, *got)
+		}
+	}
+
+	check("contain, over-panned", NewTRS(80, -30, 0, 1, 1), ConstrainContain, 50, 0)
+	check("contain, inside", NewTRS(10, 20, 0, 1, 1), ConstrainContain, 10, 20)
+	check("contain, too large", NewTRS(10, 20, 0, 4, 1), ConstrainContain, -50, 20)
+	check("cover, over-panned", NewTRS(20, -150, 0, 4, 4), ConstrainCover, 0, -100)
+	check("cover, covering", NewTRS(-30, -60, 0, 4, 4), ConstrainCover, -30, -60)
+	check("cover, too small", NewTRS(0, 0, 0, 1, 1), ConstrainCover, 25, 25)
+}
+
+func TestZoomAbout(t *testing.T) {
+	tr := NewTRS(10, 20, 0.4, 2, 2)
+	// The content points under the cursor and one unit to its right.
+	ux, uy, err := tr.InverseApply(50, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := tr.ApplyXY(ux+1, uy)
+	if err := tr.ZoomAbout(3, 50, 60); err != nil {
+		t.Fatal(err)
+	}
+	if p := tr.ApplyXY(ux, uy); !near(p.X, 50) || !near(p.Y, 60) {
+		t.Errorf("ZoomAbout: cursor content moved to %v, want {50 60}", p)
+	}
+	after := tr.ApplyXY(ux+1, uy)
+	if !near(after.X-50, 3*(before.X-50)) || !near(after.Y-60, 3*(before.Y-60)) {
+		t.Errorf("ZoomAbout: neighbour moved from %v to %v, want 3x further from the cursor", before, after)
+	}
+	if got := tr.GetRotation(); !near(got, 0.4) {
+		t.Errorf("ZoomAbout: rotation got %v, want 0.4", got)
+	}
+
+	saved := *tr
+	for _, f := range []float64{0, -2, math.NaN()} {
+		if err := tr.ZoomAbout(f, 1, 1); err != ErrInvalidZoomFactor || *tr != saved {
+			t.Errorf("ZoomAbout(%v): got %v, %v, want %v and no change", f, err, *tr, ErrInvalidZoomFactor)
+		}
+	}
+}